@@ -1,23 +1,232 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"receipt-processor/pkg/api"
+	"receipt-processor/pkg/auth"
+	"receipt-processor/pkg/ratelimit"
+	"receipt-processor/pkg/receiptpb"
+	"receipt-processor/pkg/store"
+	"receipt-processor/pkg/telemetry"
+	"receipt-processor/pkg/utils"
+	"receipt-processor/pkg/webhooks"
 
 	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
 )
 
 func main() {
+	//Serve the gRPC API on a separate port when -grpc-addr or GRPC_ADDR is set
+	grpcAddr := flag.String("grpc-addr", os.Getenv("GRPC_ADDR"), "address to serve the gRPC API on, such as :9090 (disabled when empty)")
+	flag.Parse()
+
+	//Configure tracing from the OTEL_* environment variables
+	shutdownTracing, err := telemetry.SetupTracing(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer shutdownTracing(context.Background())
+
+	//Create the in-memory store, expiring earned points after POINTS_EXPIRY_MONTHS when set
+	var storeOptions []store.Option
+	expiryMonths := 0
+	if value := os.Getenv("POINTS_EXPIRY_MONTHS"); value != "" {
+		expiryMonths, err = strconv.Atoi(value)
+		if err != nil || expiryMonths < 0 {
+			log.Fatalf("POINTS_EXPIRY_MONTHS must be a non-negative integer, got %q", value)
+		}
+		storeOptions = append(storeOptions, store.WithPointExpiry(expiryMonths))
+	}
+
+	//Cap what each user earns per day with DAILY_POINTS_CAP and DAILY_RETAILER_RECEIPT_LIMIT when set
+	var caps store.EarningCaps
+	if value := os.Getenv("DAILY_POINTS_CAP"); value != "" {
+		caps.DailyPoints, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || caps.DailyPoints < 0 {
+			log.Fatalf("DAILY_POINTS_CAP must be a non-negative integer, got %q", value)
+		}
+	}
+	if value := os.Getenv("DAILY_RETAILER_RECEIPT_LIMIT"); value != "" {
+		caps.DailyRetailerReceipts, err = strconv.Atoi(value)
+		if err != nil || caps.DailyRetailerReceipts < 0 {
+			log.Fatalf("DAILY_RETAILER_RECEIPT_LIMIT must be a non-negative integer, got %q", value)
+		}
+	}
+	storeOptions = append(storeOptions, store.WithEarningCaps(caps))
+	receipts := store.New(storeOptions...)
+
 	//Establish a new router instance
 	router := mux.NewRouter()
 
-	//Define API endpoints
-	router.HandleFunc("/receipts/process", api.ProcessReceipt).Methods("POST")
-	router.HandleFunc("/receipts/{id}/points", api.GetPoints).Methods("GET")
+	//Tag requests with an ID, trace them and recover from handler panics
+	router.Use(api.RequestID, api.Trace, api.Recover)
+
+	//Require API keys and/or bearer tokens when either is configured
+	var keys *auth.APIKeys
+	if path := os.Getenv("API_KEYS_FILE"); path != "" {
+		keys, err = auth.LoadAPIKeys(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	var tokens *auth.JWTVerifier
+	if source := os.Getenv("JWKS_SOURCE"); source != "" {
+		tokens, err = auth.NewJWTVerifier(source, auth.JWTOptions{
+			Issuer:   os.Getenv("JWT_ISSUER"),
+			Audience: os.Getenv("JWT_AUDIENCE"),
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if keys != nil || tokens != nil {
+		router.Use(api.Authenticate(keys, tokens))
+	} else {
+		log.Println("neither API_KEYS_FILE nor JWKS_SOURCE is set, receipt endpoints are unauthenticated")
+	}
+
+	//Limit each caller's request rate per route when RATE_LIMITS_FILE is set
+	if path := os.Getenv("RATE_LIMITS_FILE"); path != "" {
+		limits, err := ratelimit.LoadConfig(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		router.Use(api.RateLimit(ratelimit.New(limits)))
+	}
+
+	//Respond with JSON errors for unknown routes and methods
+	router.NotFoundHandler = http.HandlerFunc(api.NotFound)
+	router.MethodNotAllowedHandler = http.HandlerFunc(api.MethodNotAllowed)
+
+	//Score receipts in the background with PROCESSING_WORKERS workers when set
+	var serverOptions []api.Option
+	if value := os.Getenv("PROCESSING_WORKERS"); value != "" {
+		workers, err := strconv.Atoi(value)
+		if err != nil || workers < 1 {
+			log.Fatalf("PROCESSING_WORKERS must be a positive integer, got %q", value)
+		}
+		queueSize := 100
+		if value := os.Getenv("PROCESSING_QUEUE_SIZE"); value != "" {
+			queueSize, err = strconv.Atoi(value)
+			if err != nil || queueSize < 1 {
+				log.Fatalf("PROCESSING_QUEUE_SIZE must be a positive integer, got %q", value)
+			}
+		}
+		serverOptions = append(serverOptions, api.WithAsyncProcessing(workers, queueSize))
+	}
+
+	//Accept purchase dates and times in the ;-separated layouts in PURCHASE_DATE_LAYOUTS and PURCHASE_TIME_LAYOUTS when set
+	layouts := utils.DefaultLayouts
+	if value := os.Getenv("PURCHASE_DATE_LAYOUTS"); value != "" {
+		layouts.Dates = strings.Split(value, ";")
+	}
+	if value := os.Getenv("PURCHASE_TIME_LAYOUTS"); value != "" {
+		layouts.Times = strings.Split(value, ";")
+	}
+	if err := layouts.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	serverOptions = append(serverOptions, api.WithLayouts(layouts))
+
+	//POST signed events to the subscribers in WEBHOOKS_FILE when set
+	var dispatcher *webhooks.Dispatcher
+	if path := os.Getenv("WEBHOOKS_FILE"); path != "" {
+		subscriptions, err := webhooks.LoadSubscriptions(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		dispatcher = webhooks.NewDispatcher(subscriptions, webhooks.Options{})
+		serverOptions = append(serverOptions, api.WithWebhooks(dispatcher))
+	}
+	receiptServer := api.NewServer(receipts, serverOptions...)
+
+	//Define API endpoints generated from the OpenAPI spec under /v1 and /v2
+	v1 := router.NewRoute().Subrouter()
+	v1.Use(api.Version(api.V1))
+	api.RegisterRoutesAt(v1, "/v1", receiptServer)
+	v2 := router.NewRoute().Subrouter()
+	v2.Use(api.Version(api.V2))
+	api.RegisterRoutesAt(v2, "/v2", receiptServer)
+
+	//Keep serving the unprefixed routes as deprecated aliases of /v1
+	unversioned := router.NewRoute().Subrouter()
+	unversioned.Use(api.Version(api.V1), api.Deprecated("/v1"))
+	api.RegisterRoutes(unversioned, receiptServer)
+	router.HandleFunc("/openapi.yml", api.OpenAPISpec).Methods("GET")
+	router.Handle("/graphql", receiptServer.GraphQL()).Methods("POST")
+
+	//Start the HTTP server and stop it cleanly on interrupt so buffered spans are flushed
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	//Post expiry debits in the background while the server runs
+	if expiryMonths > 0 {
+		go receipts.RunExpiryJob(ctx, time.Hour)
+	}
+
+	server := &http.Server{Addr: ":8080", Handler: router}
+	server.RegisterOnShutdown(receiptServer.CloseStreams)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	//Serve the same receipts over gRPC, with the same credentials and panic recovery as the REST API
+	var grpcServer *grpc.Server
+	if *grpcAddr != "" {
+		interceptors := []grpc.UnaryServerInterceptor{api.GRPCRecover}
+		if keys != nil || tokens != nil {
+			interceptors = append(interceptors, api.GRPCAuthenticate(keys, tokens))
+		}
+		grpcServer = grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+		receiptpb.RegisterReceiptProcessorServer(grpcServer, api.NewGRPCServer(receiptServer))
+
+		listener, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
 
-	//Start the HTTP server
-	log.Fatal(http.ListenAndServe(":8080", router))
+	//Give in-flight requests 30 seconds to finish, ending open receipt streams straight away
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println(err)
+	}
+	if grpcServer != nil {
+		//Drain gRPC calls within the same deadline, cancelling any still running when it passes
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			grpcServer.Stop()
+			<-stopped
+		}
+	}
 
+	//Finish scoring any receipts still queued, then stop retrying webhook deliveries
+	receiptServer.Close()
+	dispatcher.Close()
 }
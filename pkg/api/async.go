@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+)
+
+// processJob is a validated receipt waiting for a worker to score it
+type processJob struct {
+	ctx     context.Context
+	id      string
+	receipt models.Receipt
+	// original is the purchase date and time as submitted
+	original store.Original
+}
+
+// WithAsyncProcessing makes ProcessReceipt queue receipts for a pool of workers and
+// answer 202 straight away. Submissions are refused once queueSize receipts are waiting.
+func WithAsyncProcessing(workers, queueSize int) Option {
+	return func(s *Server) {
+		s.queue = make(chan processJob, queueSize)
+		for i := 0; i < workers; i++ {
+			s.workers.Add(1)
+			go s.work()
+		}
+	}
+}
+
+// Close waits for the queued receipts to be stored. Call it once the HTTP server has stopped
+// handing it new submissions.
+func (s *Server) Close() {
+	if s.queue == nil {
+		return
+	}
+	s.closeQueue.Do(func() { close(s.queue) })
+	s.workers.Wait()
+}
+
+// enqueue hands the receipt to the workers, reporting false when the queue is full
+func (s *Server) enqueue(ctx context.Context, id string, receipt models.Receipt, original store.Original) bool {
+	s.processing.Store(id, userID(ctx))
+
+	// Keep the request's principal, ID and span for the worker after the response is sent
+	select {
+	case s.queue <- processJob{ctx: context.WithoutCancel(ctx), id: id, receipt: receipt, original: original}:
+		return true
+	default:
+		s.processing.Delete(id)
+		return false
+	}
+}
+
+func (s *Server) work() {
+	defer s.workers.Done()
+
+	for job := range s.queue {
+		s.processJob(job)
+	}
+}
+
+func (s *Server) processJob(job processJob) {
+	// The receipt stops being reported as processing once it is stored, or once it is marked
+	// failed if scoring panics, so polling clients always get an answer
+	defer s.processing.Delete(job.id)
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Printf("panic processing receipt %s (request ID %s): %v\n%s", job.id, RequestIDFromContext(job.ctx), rec, debug.Stack())
+			s.failed.Store(job.id, userID(job.ctx))
+		}
+	}()
+
+	s.process(job.ctx, job.id, job.receipt, job.original)
+}
+
+// processingOwner reports whether the receipt is still queued or being scored, and who submitted it
+func (s *Server) processingOwner(id string) (string, bool) {
+	owner, ok := s.processing.Load(id)
+	if !ok {
+		return "", false
+	}
+	return owner.(string), true
+}
+
+// failedOwner reports whether scoring the queued receipt failed before it was stored, and who submitted it
+func (s *Server) failedOwner(id string) (string, bool) {
+	owner, ok := s.failed.Load(id)
+	if !ok {
+		return "", false
+	}
+	return owner.(string), true
+}
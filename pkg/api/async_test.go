@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+
+	"github.com/gorilla/mux"
+)
+
+func TestAsyncProcessing(t *testing.T) {
+	// Start without workers so the first receipt stays queued
+	server := NewServer(store.New(), WithAsyncProcessing(0, 1))
+	router := mux.NewRouter()
+	RegisterRoutes(router, server)
+
+	receipt := `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [{"shortDescription": "Dasani", "price": "1.40"}], "total": "1.40"}`
+
+	submit := func(body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest("POST", "/receipts/process", strings.NewReader(body)))
+		return recorder
+	}
+	points := func(id string) models.GetPointsResponse {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest("GET", "/receipts/"+id+"/points", nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+		}
+		var response models.GetPointsResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Error parsing response: %v", err)
+		}
+		return response
+	}
+
+	// Invalid receipts are still rejected straight away
+	if recorder := submit(`{"retailer": "Target"}`); recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+
+	recorder := submit(receipt)
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf("Expected status code %d, got %d", http.StatusAccepted, recorder.Code)
+	}
+	var queued models.PostReceiptResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &queued); err != nil {
+		t.Fatalf("Error parsing response: %v", err)
+	}
+	if response := points(queued.ID); response.Status != models.ReceiptStatusProcessing || response.Points != 0 {
+		t.Errorf("Expected a processing receipt without points, got %+v", response)
+	}
+
+	// The queue only holds one receipt
+	if recorder := submit(receipt); recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d with a full queue, got %d", http.StatusServiceUnavailable, recorder.Code)
+	}
+
+	// A worker drains the queue before Close returns
+	server.workers.Add(1)
+	go server.work()
+	server.Close()
+
+	if response := points(queued.ID); response.Status != models.ReceiptStatusApproved || response.Points != 13 {
+		t.Errorf("Expected the scored receipt, got %+v", response)
+	}
+}
+
+// panicOnce is a context whose first lookup panics, standing in for a rule that blows up while scoring
+type panicOnce struct {
+	context.Context
+	panicked bool
+}
+
+func (c *panicOnce) Value(key any) any {
+	if !c.panicked {
+		c.panicked = true
+		panic("scoring failed")
+	}
+	return c.Context.Value(key)
+}
+
+func TestAsyncProcessingFailure(t *testing.T) {
+	server := NewServer(store.New(), WithAsyncProcessing(0, 1))
+	router := mux.NewRouter()
+	RegisterRoutes(router, server)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("POST", "/receipts/process", strings.NewReader(`{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [{"shortDescription": "Dasani", "price": "1.40"}], "total": "1.40"}`)))
+	var queued models.PostReceiptResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &queued); err != nil {
+		t.Fatalf("Error parsing response: %v", err)
+	}
+
+	// Score the queued receipt as a worker would, panicking before it is stored
+	job := <-server.queue
+	job.ctx = &panicOnce{Context: job.ctx}
+	server.processJob(job)
+
+	// Polling ends with a failed status rather than a missing receipt
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/receipts/"+queued.ID+"/points", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+	}
+	var response models.GetPointsResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Error parsing response: %v", err)
+	}
+	if response.Status != models.ReceiptStatusFailed || response.Points != 0 {
+		t.Errorf("Expected a failed receipt without points, got %+v", response)
+	}
+}
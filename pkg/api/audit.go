@@ -0,0 +1,92 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"receipt-processor/pkg/auth"
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+)
+
+func (s *Server) ListAuditEvents(ctx context.Context, request ListAuditEventsRequestObject) (ListAuditEventsResponseObject, error) {
+	events := s.store.AuditLog(auditFilter(request.Params))
+
+	response := ListAuditEvents200JSONResponse{Events: make([]models.AuditEvent, len(events))}
+	for i, event := range events {
+		response.Events[i] = auditEvent(event)
+	}
+	return response, nil
+}
+
+func (s *Server) ExportAuditEvents(ctx context.Context, request ExportAuditEventsRequestObject) (ExportAuditEventsResponseObject, error) {
+	events := s.store.AuditLog(auditFilter(ListAuditEventsParams(request.Params)))
+
+	// Encode writes each event followed by a newline
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, event := range events {
+		if err := encoder.Encode(auditEvent(event)); err != nil {
+			return nil, err
+		}
+	}
+	return ExportAuditEvents200ApplicationXNdjsonResponse{Body: &body, ContentLength: int64(body.Len())}, nil
+}
+
+// audit records the event as performed by the request's caller
+func (s *Server) audit(ctx context.Context, event store.AuditEvent) {
+	event.Actor = "anonymous"
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		event.Actor = principal.Name
+	}
+	event.RequestID = RequestIDFromContext(ctx)
+	s.store.RecordAudit(event)
+}
+
+func auditFilter(params ListAuditEventsParams) store.AuditFilter {
+	var filter store.AuditFilter
+	if params.Action != nil {
+		filter.Action = store.AuditAction(*params.Action)
+	}
+	if params.ReceiptID != nil {
+		filter.ReceiptID = *params.ReceiptID
+	}
+	if params.UserID != nil {
+		filter.UserID = *params.UserID
+	}
+	if params.Actor != nil {
+		filter.Actor = *params.Actor
+	}
+	if params.Since != nil {
+		filter.Since = *params.Since
+	}
+	if params.Until != nil {
+		filter.Until = *params.Until
+	}
+	return filter
+}
+
+func auditEvent(event store.AuditEvent) models.AuditEvent {
+	response := models.AuditEvent{
+		ID:           event.ID,
+		Action:       models.AuditAction(event.Action),
+		Actor:        event.Actor,
+		PointsBefore: event.PointsBefore,
+		PointsAfter:  event.PointsAfter,
+		Timestamp:    event.Timestamp,
+	}
+	if event.RequestID != "" {
+		response.RequestID = &event.RequestID
+	}
+	if event.ReceiptID != "" {
+		response.ReceiptID = &event.ReceiptID
+	}
+	if event.UserID != "" {
+		response.UserID = &event.UserID
+	}
+	if event.Reason != "" {
+		response.Reason = &event.Reason
+	}
+	return response
+}
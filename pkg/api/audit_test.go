@@ -0,0 +1,115 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"receipt-processor/pkg/auth"
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+)
+
+func TestAuditLogRecordsPointChanges(t *testing.T) {
+	receipts := store.New()
+	router := newTestRouterWithStore(receipts)
+	router.Use(RequestID)
+
+	user := auth.Principal{Name: "user-1", Subject: "user-1", Scopes: []auth.Scope{auth.ScopeSubmit, auth.ScopeRead}}
+	support := auth.Principal{Name: "support-console", Scopes: []auth.Scope{auth.ScopeAdmin}}
+	receipt := `{"retailer": "M&M Corner Market", "purchaseDate": "2022-03-20", "purchaseTime": "14:33", "items": [{"shortDescription": "Gatorade", "price": "2.25"}, {"shortDescription": "Gatorade", "price": "2.25"}, {"shortDescription": "Gatorade", "price": "2.25"}, {"shortDescription": "Gatorade", "price": "2.25"}], "total": "9.00"}`
+
+	send := func(principal auth.Principal, method, path, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set(requestIDHeader, "req-"+method)
+		request = request.WithContext(auth.WithPrincipal(request.Context(), principal))
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		if recorder.Code >= 300 {
+			t.Fatalf("%s %s returned %d: %s", method, path, recorder.Code, recorder.Body.String())
+		}
+		return recorder
+	}
+
+	var posted models.PostReceiptResponse
+	json.Unmarshal(send(user, "POST", "/receipts/process", receipt).Body.Bytes(), &posted)
+	send(support, "PUT", "/receipts/"+posted.ID+"?reason=rules%20changed", receipt)
+	send(user, "POST", "/users/user-1/redemptions", `{"points": 100, "reward": "$5 gift card"}`)
+	send(support, "DELETE", "/receipts/"+posted.ID+"?reason=fraud", "")
+
+	var response models.AuditLogResponse
+	if err := json.Unmarshal(send(support, "GET", "/audit/events", "").Body.Bytes(), &response); err != nil {
+		t.Fatalf("Error parsing response: %v", err)
+	}
+
+	// Define slice of expected events
+	expected := []struct {
+		action       models.AuditAction
+		actor        string
+		requestID    string
+		pointsBefore int64
+		pointsAfter  int64
+	}{
+		{models.AuditActionSubmitted, "user-1", "req-POST", 0, 109},
+		{models.AuditActionRecalculated, "support-console", "req-PUT", 109, 109},
+		{models.AuditActionRedeemed, "user-1", "req-POST", 109, 9},
+		{models.AuditActionDeleted, "support-console", "req-DELETE", 109, 0},
+	}
+	if len(response.Events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d", len(expected), len(response.Events))
+	}
+	for i, event := range response.Events {
+		want := expected[i]
+		if event.Action != want.action || event.Actor != want.actor || event.PointsBefore != want.pointsBefore || event.PointsAfter != want.pointsAfter {
+			t.Errorf("Event %d: expected %+v, got %+v", i, want, event)
+		}
+		if event.RequestID == nil || *event.RequestID != want.requestID {
+			t.Errorf("Event %d: expected request ID %s, got %v", i, want.requestID, event.RequestID)
+		}
+	}
+
+	// The export holds one event per line and honours the same filters
+	recorder := send(support, "GET", "/audit/events/export?actor=support-console", "")
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/x-ndjson" {
+		t.Errorf("Expected Content-Type application/x-ndjson, got %s", contentType)
+	}
+	var exported []models.AuditEvent
+	scanner := bufio.NewScanner(recorder.Body)
+	for scanner.Scan() {
+		var event models.AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("Error parsing line %q: %v", scanner.Text(), err)
+		}
+		exported = append(exported, event)
+	}
+	if len(exported) != 2 || exported[0].Action != models.AuditActionRecalculated || exported[1].Action != models.AuditActionDeleted {
+		t.Errorf("Unexpected export %+v", exported)
+	}
+}
+
+func TestAuditLogRequiresAdmin(t *testing.T) {
+	keys, err := auth.NewAPIKeys([]auth.APIKeyConfig{
+		{Name: "reader", SHA256: auth.HashKey("reader-key"), Scopes: []auth.Scope{auth.ScopeRead}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := newTestRouter()
+	router.Use(Authenticate(keys, nil))
+
+	for _, path := range []string{"/audit/events", "/audit/events/export"} {
+		request := httptest.NewRequest("GET", path, nil)
+		request.Header.Set(apiKeyHeader, "reader-key")
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, request)
+
+		// Check for expected status code
+		if recorder.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d for %s, got %d", http.StatusForbidden, path, recorder.Code)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"receipt-processor/pkg/auth"
+
+	"github.com/gorilla/mux"
+)
+
+const apiKeyHeader = "X-API-Key"
+
+// routeScopes lists the scope each protected route requires, keyed by method and route template.
+// Routes missing from the map, such as /openapi.yml, are public.
+var routeScopes = map[string]auth.Scope{
+	"GET /receipts":                auth.ScopeRead,
+	"POST /receipts/process":       auth.ScopeSubmit,
+	"POST /receipts/batch":         auth.ScopeSubmit,
+	"POST /receipts/explain":       auth.ScopeRead,
+	"GET /receipts/stream":         auth.ScopeRead,
+	"GET /receipts/pending":        auth.ScopeAdmin,
+	"POST /receipts/{id}/approve":  auth.ScopeAdmin,
+	"POST /receipts/{id}/reject":   auth.ScopeAdmin,
+	"PUT /receipts/{id}":           auth.ScopeAdmin,
+	"DELETE /receipts/{id}":        auth.ScopeAdmin,
+	"GET /receipts/{id}/revisions": auth.ScopeAdmin,
+	"GET /receipts/{id}/points":    auth.ScopeRead,
+	"GET /users/{id}/points":       auth.ScopeRead,
+	"GET /users/{id}/receipts":     auth.ScopeRead,
+	"POST /users/{id}/redemptions": auth.ScopeSubmit,
+	"GET /users/{id}/ledger":       auth.ScopeRead,
+	"POST /users/{id}/adjustments": auth.ScopeAdmin,
+	"GET /audit/events":            auth.ScopeAdmin,
+	"GET /audit/events/export":     auth.ScopeAdmin,
+	"GET /webhooks/deliveries":     auth.ScopeAdmin,
+	"GET /webhooks/dead-letters":   auth.ScopeAdmin,
+	"POST /graphql":                auth.ScopeRead,
+}
+
+// Authenticate requires a caller with the route's scope on every protected route. Callers
+// present either a bearer token checked by tokens or an X-API-Key checked by keys; either
+// may be nil to disable that method.
+func Authenticate(keys *auth.APIKeys, tokens *auth.JWTVerifier) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scope, protected := requiredScope(r)
+			if !protected {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, err := authenticate(r.Header.Get("Authorization"), r.Header.Get(apiKeyHeader), keys, tokens)
+			if err != nil {
+				writeError(w, http.StatusUnauthorized, codeUnauthorized, err.Error())
+				return
+			}
+
+			if !principal.HasScope(scope) {
+				writeError(w, http.StatusForbidden, codeForbidden, fmt.Sprintf("%q is missing the %q scope", principal.Name, scope))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// authenticate checks the caller's Authorization header or API key, whichever is present
+func authenticate(authorization, key string, keys *auth.APIKeys, tokens *auth.JWTVerifier) (auth.Principal, error) {
+	if authorization != "" && tokens != nil {
+		token, ok := strings.CutPrefix(authorization, "Bearer ")
+		if !ok {
+			return auth.Principal{}, errors.New("the Authorization header must use the Bearer scheme")
+		}
+		return tokens.Verify(token)
+	}
+
+	if key != "" && keys != nil {
+		principal, ok := keys.Authenticate(key)
+		if !ok {
+			return auth.Principal{}, errors.New("invalid API key")
+		}
+		return principal, nil
+	}
+
+	return auth.Principal{}, errors.New("missing credentials")
+}
+
+func requiredScope(r *http.Request) (auth.Scope, bool) {
+	scope, ok := routeScopes[routeKey(r)]
+	return scope, ok
+}
+
+// routeKey identifies the matched route by method and unversioned path template, such as
+// "GET /receipts/{id}/points" for both /v1/receipts/{id}/points and /v2/receipts/{id}/points
+func routeKey(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return ""
+	}
+	return r.Method + " " + unversioned(template)
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"receipt-processor/pkg/auth"
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+)
+
+func TestAuthenticateMiddleware(t *testing.T) {
+	keys, err := auth.NewAPIKeys([]auth.APIKeyConfig{
+		{Name: "pos-terminal", SHA256: auth.HashKey("submit-key"), Scopes: []auth.Scope{auth.ScopeSubmit}},
+		{Name: "dashboard", SHA256: auth.HashKey("read-key"), Scopes: []auth.Scope{auth.ScopeRead}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := newTestRouter()
+	router.Use(Authenticate(keys, nil))
+
+	validReceipt := `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [{"shortDescription": "Dasani", "price": "1.40"}], "total": "1.40"}`
+
+	// Define slice of test cases
+	testCases := []struct {
+		description    string
+		method         string
+		requestPath    string
+		requestBody    string
+		apiKey         string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			description:    "Missing key",
+			method:         "POST",
+			requestPath:    "/receipts/process",
+			requestBody:    validReceipt,
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   codeUnauthorized,
+		},
+		{
+			description:    "Unknown key",
+			method:         "POST",
+			requestPath:    "/receipts/process",
+			requestBody:    validReceipt,
+			apiKey:         "not-a-key",
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   codeUnauthorized,
+		},
+		{
+			description:    "Key without submit scope",
+			method:         "POST",
+			requestPath:    "/receipts/process",
+			requestBody:    validReceipt,
+			apiKey:         "read-key",
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   codeForbidden,
+		},
+		{
+			description:    "Key with submit scope",
+			method:         "POST",
+			requestPath:    "/receipts/process",
+			requestBody:    validReceipt,
+			apiKey:         "submit-key",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "Submit key can't redeem a user's points",
+			method:         "POST",
+			requestPath:    "/users/user-1/redemptions",
+			requestBody:    `{"points": 10, "reward": "$1 gift card"}`,
+			apiKey:         "submit-key",
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   codeForbidden,
+		},
+		{
+			description:    "Key without read scope",
+			method:         "GET",
+			requestPath:    "/receipts/12345/points",
+			apiKey:         "submit-key",
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   codeForbidden,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			request := httptest.NewRequest(testCase.method, testCase.requestPath, strings.NewReader(testCase.requestBody))
+			if testCase.apiKey != "" {
+				request.Header.Set(apiKeyHeader, testCase.apiKey)
+			}
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, request)
+
+			// Check for expected status code
+			if recorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, recorder.Code)
+			}
+
+			if testCase.expectedCode == "" {
+				return
+			}
+
+			// Extract error code from json response
+			var response models.ErrorResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Error parsing response: %v", err)
+			}
+			if response.Code != testCase.expectedCode {
+				t.Errorf("Want code %q, got %q", testCase.expectedCode, response.Code)
+			}
+		})
+	}
+}
+
+func TestBearerTokenOwnership(t *testing.T) {
+	// Issue tokens from a throwaway RSA key published as a local JWKS file
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: &private.PublicKey, KeyID: "test", Use: "sig"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := auth.NewJWTVerifier(path, auth.JWTOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: private}, (&jose.SignerOptions{}).WithHeader("kid", "test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenFor := func(subject string) string {
+		claims := jwt.Claims{Subject: subject, Expiry: jwt.NewNumericDate(time.Now().Add(time.Hour))}
+		token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "Bearer " + token
+	}
+
+	receipts := store.New()
+	router := newTestRouterWithStore(receipts)
+	router.Use(Authenticate(nil, tokens))
+
+	// Submit a receipt as user-1
+	request := httptest.NewRequest("POST", "/receipts/process", strings.NewReader(`{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [{"shortDescription": "Dasani", "price": "1.40"}], "total": "1.40"}`))
+	request.Header.Set("Authorization", tokenFor("user-1"))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	var submitted models.PostReceiptResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &submitted); err != nil {
+		t.Fatalf("Error parsing response: %v", err)
+	}
+
+	// Check the submitter is recorded on the stored receipt
+	if stored, ok := receipts.Receipt(submitted.ID); !ok || stored.UserID != "user-1" {
+		t.Fatalf("Expected receipt %s to be stored for user-1, got %+v", submitted.ID, stored)
+	}
+
+	testCases := []struct {
+		description    string
+		authorization  string
+		expectedStatus int
+	}{
+		{"Owner", tokenFor("user-1"), http.StatusOK},
+		{"Another user", tokenFor("user-2"), http.StatusNotFound},
+		{"Malformed header", "Basic dXNlcjpwYXNz", http.StatusUnauthorized},
+		{"No token", "", http.StatusUnauthorized},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/receipts/"+submitted.ID+"/points", nil)
+			if testCase.authorization != "" {
+				request.Header.Set("Authorization", testCase.authorization)
+			}
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, request)
+
+			// Check for expected status code
+			if recorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, recorder.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"receipt-processor/pkg/models"
+)
+
+// maxBatchSize is the most receipts a batch may hold
+const maxBatchSize = 100
+
+func (s *Server) ProcessReceipts(ctx context.Context, request ProcessReceiptsRequestObject) (ProcessReceiptsResponseObject, error) {
+	receipts := request.Body.Receipts
+	if len(receipts) == 0 || len(receipts) > maxBatchSize {
+		return ProcessReceipts400JSONResponse{Code: codeValidationFailed, Errors: []string{fmt.Sprintf("a batch must hold between 1 and %d receipts, got %d", maxBatchSize, len(receipts))}}, nil
+	}
+
+	// Process each receipt on its own so one invalid receipt doesn't reject the rest
+	response := ProcessReceipts200JSONResponse{Results: make([]models.BatchReceiptResult, len(receipts))}
+	for i := range receipts {
+		result, err := s.ProcessReceipt(ctx, ProcessReceiptRequestObject{Body: &receipts[i]})
+		if err != nil {
+			return nil, err
+		}
+
+		switch result := result.(type) {
+		case ProcessReceipt200JSONResponse:
+			response.Results[i].ID = &result.ID
+		case ProcessReceipt202JSONResponse:
+			queued := true
+			response.Results[i].ID, response.Results[i].Queued = &result.ID, &queued
+		case ProcessReceipt400JSONResponse:
+			response.Results[i].Errors = &result.Errors
+		case ProcessReceipt503JSONResponse:
+			response.Results[i].Errors = &result.Errors
+		}
+	}
+	return response, nil
+}
+
+func (s *Server) ExplainPoints(ctx context.Context, request ExplainPointsRequestObject) (ExplainPointsResponseObject, error) {
+	points, breakdown, validationErrors := s.scoreReceipt(ctx, *request.Body)
+	if len(validationErrors) > 0 {
+		return ExplainPoints400JSONResponse{Code: codeValidationFailed, Errors: validationErrors}, nil
+	}
+	return ExplainPoints200JSONResponse(pointsBreakdown(points, breakdown)), nil
+}
@@ -0,0 +1,336 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/openapi"
+	"receipt-processor/pkg/store"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gorilla/mux"
+)
+
+func TestOpenAPIContract(t *testing.T) {
+	// Load the embedded spec and build a router that resolves requests to its operations
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("Error loading spec: %v", err)
+	}
+	specRouter, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		t.Fatalf("Error building spec router: %v", err)
+	}
+
+	// The audit export is NDJSON and the receipt stream server-sent events, which kin-openapi reads as plain strings
+	openapi3filter.RegisterBodyDecoder("application/x-ndjson", openapi3filter.FileBodyDecoder)
+	defer openapi3filter.UnregisterBodyDecoder("application/x-ndjson")
+	openapi3filter.RegisterBodyDecoder("text/event-stream", openapi3filter.FileBodyDecoder)
+	defer openapi3filter.UnregisterBodyDecoder("text/event-stream")
+
+	router := newVersionedTestRouter(store.New())
+
+	// Queue receipts without workers to score them, so they are accepted with a 202
+	asyncServer := NewServer(store.New(), WithAsyncProcessing(0, 1))
+	asyncRouter := mux.NewRouter()
+	RegisterRoutes(asyncRouter, asyncServer)
+
+	// Publish a receipt, then end streams as soon as they have replayed the events they missed
+	streamServer := NewServer(store.New())
+	streamRouter := mux.NewRouter()
+	RegisterRoutes(streamRouter, streamServer)
+	submitReceipt(t, streamRouter, `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [{"shortDescription": "Dasani", "price": "1.40"}], "total": "1.40"}`)
+	streamServer.CloseStreams()
+
+	// Store a receipt so the points lookup has something to find
+	storedID := submitReceipt(t, router, `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [{"shortDescription": "Mountain Dew 12PK", "price": "6.49"}], "total": "6.49"}`)
+
+	// Define slice of test cases
+	testCases := []struct {
+		description    string
+		method         string
+		requestPath    string
+		requestBody    string
+		requestHeader  map[string]string
+		router         http.Handler
+		expectedStatus int
+	}{
+		{
+			description:    "Process valid receipt",
+			method:         "POST",
+			requestPath:    "/receipts/process",
+			requestBody:    `{"retailer": "Walgreens", "purchaseDate": "2022-01-02", "purchaseTime": "08:13", "items": [{"shortDescription": "Dasani", "price": "1.40"}], "total": "1.40"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "Process invalid JSON",
+			method:         "POST",
+			requestPath:    "/receipts/process",
+			requestBody:    `invalid`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "Process receipt missing fields",
+			method:         "POST",
+			requestPath:    "/receipts/process",
+			requestBody:    `{"retailer": "Walgreens"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "Process receipt in scanned formats",
+			method:         "POST",
+			requestPath:    "/receipts/process",
+			requestBody:    `{"retailer": "Walgreens", "purchaseDate": "01/02/2022", "purchaseTime": "8:13 AM", "items": [{"shortDescription": "Dasani", "price": "1.40"}], "total": "1.40"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "Queue receipt in asynchronous mode",
+			method:         "POST",
+			requestPath:    "/receipts/process",
+			requestBody:    `{"retailer": "Walgreens", "purchaseDate": "2022-01-02", "purchaseTime": "08:13", "items": [{"shortDescription": "Dasani", "price": "1.40"}], "total": "1.40"}`,
+			router:         asyncRouter,
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			description:    "Stream receipts since the first event",
+			method:         "GET",
+			requestPath:    "/receipts/stream",
+			requestHeader:  map[string]string{"Last-Event-ID": "0"},
+			router:         streamRouter,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "Process a batch of receipts",
+			method:         "POST",
+			requestPath:    "/receipts/batch",
+			requestBody:    `{"receipts": [{"retailer": "Walgreens", "purchaseDate": "2022-01-02", "purchaseTime": "08:13", "items": [{"shortDescription": "Dasani", "price": "1.40"}], "total": "1.40"}, {"retailer": "Walgreens"}]}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "Process an empty batch",
+			method:         "POST",
+			requestPath:    "/receipts/batch",
+			requestBody:    `{"receipts": []}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "Explain points",
+			method:         "POST",
+			requestPath:    "/receipts/explain",
+			requestBody:    `{"retailer": "Walgreens", "purchaseDate": "2022-01-02", "purchaseTime": "08:13", "items": [{"shortDescription": "Dasani", "price": "1.40"}], "total": "1.40"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "Get points for stored receipt",
+			method:         "GET",
+			requestPath:    "/receipts/" + storedID + "/points",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "Get points with breakdown from v2",
+			method:         "GET",
+			requestPath:    "/v2/receipts/" + storedID + "/points",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "List receipts",
+			method:         "GET",
+			requestPath:    "/receipts?sort=-points&limit=1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "Get user balance",
+			method:         "GET",
+			requestPath:    "/users/user-1/points",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "Get user receipts",
+			method:         "GET",
+			requestPath:    "/users/user-1/receipts",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "Adjust user balance",
+			method:         "POST",
+			requestPath:    "/users/user-1/adjustments",
+			requestBody:    `{"points": 25, "reason": "Goodwill for a delayed reward"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			description:    "Adjust user balance without a reason",
+			method:         "POST",
+			requestPath:    "/users/user-1/adjustments",
+			requestBody:    `{"points": 25}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "Get user ledger",
+			method:         "GET",
+			requestPath:    "/users/user-1/ledger",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "Redeem more points than the balance",
+			method:         "POST",
+			requestPath:    "/users/user-1/redemptions",
+			requestBody:    `{"points": 1000000, "reward": "car"}`,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			description:    "Correct stored receipt",
+			method:         "PUT",
+			requestPath:    "/receipts/" + storedID,
+			requestBody:    `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [{"shortDescription": "Mountain Dew 12PK", "price": "6.49"}], "total": "6.49"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "List revisions of corrected receipt",
+			method:         "GET",
+			requestPath:    "/receipts/" + storedID + "/revisions",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "List revisions of unknown receipt",
+			method:         "GET",
+			requestPath:    "/receipts/unknown-id/revisions",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			description:    "Delete unknown receipt",
+			method:         "DELETE",
+			requestPath:    "/receipts/unknown-id",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			description:    "List pending receipts",
+			method:         "GET",
+			requestPath:    "/receipts/pending",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "Approve a receipt that is not pending",
+			method:         "POST",
+			requestPath:    "/receipts/" + storedID + "/approve",
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			description:    "Reject unknown receipt",
+			method:         "POST",
+			requestPath:    "/receipts/unknown-id/reject?reason=fraud",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			description:    "List webhook deliveries",
+			method:         "GET",
+			requestPath:    "/webhooks/deliveries",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "List webhook dead letters",
+			method:         "GET",
+			requestPath:    "/webhooks/dead-letters",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "List audit events",
+			method:         "GET",
+			requestPath:    "/audit/events?action=submitted",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "Export audit events",
+			method:         "GET",
+			requestPath:    "/audit/events/export",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:    "Get points for unknown receipt",
+			method:         "GET",
+			requestPath:    "/receipts/unknown-id/points",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			request := httptest.NewRequest(testCase.method, testCase.requestPath, strings.NewReader(testCase.requestBody))
+			for name, value := range testCase.requestHeader {
+				request.Header.Set(name, value)
+			}
+			recorder := httptest.NewRecorder()
+
+			handler := testCase.router
+			if handler == nil {
+				handler = router
+			}
+			handler.ServeHTTP(recorder, request)
+
+			// Check for expected status code
+			if recorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, recorder.Code)
+			}
+
+			// Resolve the operation in the spec
+			route, pathParams, err := specRouter.FindRoute(request)
+			if err != nil {
+				t.Fatalf("Request %s %s is not described by the spec: %v", testCase.method, testCase.requestPath, err)
+			}
+
+			// Validate status, headers and body against the documented response
+			input := &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: &openapi3filter.RequestValidationInput{
+					Request:    request,
+					PathParams: pathParams,
+					Route:      route,
+				},
+				Status:  recorder.Code,
+				Header:  recorder.Header(),
+				Body:    io.NopCloser(bytes.NewReader(recorder.Body.Bytes())),
+				Options: &openapi3filter.Options{IncludeResponseStatus: true},
+			}
+			if err := openapi3filter.ValidateResponse(context.Background(), input); err != nil {
+				t.Errorf("Response does not match the spec: %v", err)
+			}
+		})
+	}
+}
+
+func TestOpenAPISpecHandler(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	OpenAPISpec(recorder, httptest.NewRequest("GET", "/openapi.yml", nil))
+
+	// Check for expected status code
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	// Check the served document is the embedded spec
+	if !bytes.Equal(recorder.Body.Bytes(), openapi.Spec) {
+		t.Error("Expected response body to be the embedded api.yml")
+	}
+}
+
+func submitReceipt(t *testing.T, handler http.Handler, body string) string {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/receipts/process", strings.NewReader(body)))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d submitting receipt, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+
+	var response models.PostReceiptResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Error parsing response: %v", err)
+	}
+	return response.ID
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+	"receipt-processor/pkg/utils"
+)
+
+func (s *Server) CorrectReceipt(ctx context.Context, request CorrectReceiptRequestObject) (CorrectReceiptResponseObject, error) {
+	// Validate and recalculate points for the corrected Receipt
+	receipt, validationErrors := s.checkReceipt(ctx, *request.Body)
+	if len(validationErrors) > 0 {
+		return CorrectReceipt400JSONResponse{Code: codeValidationFailed, Errors: validationErrors}, nil
+	}
+	points, breakdown := utils.CalculatePointsContext(ctx, receipt)
+
+	previous, current, caps, err := s.store.CorrectReceipt(request.ID, receipt, originalOf(*request.Body), points, reason(request.Params.Reason))
+	if errors.Is(err, store.ErrReceiptNotFound) {
+		return CorrectReceipt404JSONResponse{receiptNotFound(request.ID)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	fmt.Print(utils.FormatBreakdown(utils.ApplyCaps(points, breakdown, breakdownCaps(caps))))
+
+	// Resubmitting the same contents only rescores the receipt
+	action := store.AuditCorrected
+	if reflect.DeepEqual(previous.Receipt, current.Receipt) {
+		action = store.AuditRecalculated
+	}
+	s.audit(ctx, store.AuditEvent{
+		Action:       action,
+		ReceiptID:    current.ID,
+		UserID:       current.UserID,
+		PointsBefore: previous.Points,
+		PointsAfter:  current.Points,
+		Reason:       reason(request.Params.Reason),
+	})
+
+	fmt.Printf("Corrected Receipt with ID: %s from %d to %d Points\n", current.ID, previous.Points, current.Points)
+
+	return CorrectReceipt200JSONResponse{
+		ID:             current.ID,
+		Version:        current.Version,
+		Points:         current.Points,
+		PreviousPoints: previous.Points,
+	}, nil
+}
+
+func (s *Server) DeleteReceipt(ctx context.Context, request DeleteReceiptRequestObject) (DeleteReceiptResponseObject, error) {
+	deleted, err := s.store.DeleteReceipt(request.ID, reason(request.Params.Reason))
+	if errors.Is(err, store.ErrReceiptNotFound) {
+		return DeleteReceipt404JSONResponse{receiptNotFound(request.ID)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, store.AuditEvent{
+		Action:       store.AuditDeleted,
+		ReceiptID:    deleted.ID,
+		UserID:       deleted.UserID,
+		PointsBefore: deleted.Points,
+		Reason:       reason(request.Params.Reason),
+	})
+
+	fmt.Printf("Deleted Receipt with ID: %s and reversed %d Points\n", deleted.ID, deleted.Points)
+
+	return DeleteReceipt204Response{}, nil
+}
+
+func (s *Server) ListReceiptRevisions(ctx context.Context, request ListReceiptRevisionsRequestObject) (ListReceiptRevisionsResponseObject, error) {
+	// Deleted receipts are only left in their revisions
+	revisions := s.store.Revisions(request.ID)
+	if _, ok := s.store.Receipt(request.ID); !ok && len(revisions) == 0 {
+		return ListReceiptRevisions404JSONResponse{receiptNotFound(request.ID)}, nil
+	}
+
+	response := ListReceiptRevisions200JSONResponse{Revisions: make([]models.ReceiptRevision, len(revisions))}
+	for i := range revisions {
+		revision := &revisions[i]
+		response.Revisions[i] = models.ReceiptRevision{
+			Version:   revision.Version,
+			Action:    models.AuditAction(revision.Action),
+			Receipt:   revision.Receipt.Receipt,
+			Original:  originalPurchase(revision.Original),
+			Points:    revision.Points,
+			Status:    models.ReceiptStatus(revision.Status),
+			RevisedAt: revision.RevisedAt,
+		}
+		if revision.Reason != "" {
+			response.Revisions[i].Reason = &revision.Reason
+		}
+	}
+	return response, nil
+}
+
+func reason(param *string) string {
+	if param == nil {
+		return ""
+	}
+	return *param
+}
+
+func receiptNotFound(id string) ReceiptNotFoundJSONResponse {
+	return ReceiptNotFoundJSONResponse{Code: codeReceiptNotFound, Errors: []string{fmt.Sprintf("no receipt found for ID %s", id)}}
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+)
+
+func TestCorrectAndDeleteReceiptHandlers(t *testing.T) {
+	receipts := store.New()
+	receipts.SaveReceipt(store.Receipt{ID: "r1", UserID: "user-1", Points: 6})
+	router := newTestRouterWithStore(receipts)
+
+	// Define slice of test cases
+	testCases := []struct {
+		description     string
+		method          string
+		requestPath     string
+		requestBody     string
+		expectedStatus  int
+		expectedBalance int64
+	}{
+		{
+			description:     "Invalid correction",
+			method:          "PUT",
+			requestPath:     "/receipts/r1",
+			requestBody:     `{"retailer": "Target"}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedBalance: 6,
+		},
+		{
+			description:     "Correct receipt",
+			method:          "PUT",
+			requestPath:     "/receipts/r1?reason=OCR%20misread%20total",
+			requestBody:     `{"retailer": "M&M Corner Market", "purchaseDate": "2022-03-20", "purchaseTime": "14:33", "items": [{"shortDescription": "Gatorade", "price": "2.25"}, {"shortDescription": "Gatorade", "price": "2.25"}, {"shortDescription": "Gatorade", "price": "2.25"}, {"shortDescription": "Gatorade", "price": "2.25"}], "total": "9.00"}`,
+			expectedStatus:  http.StatusOK,
+			expectedBalance: 109,
+		},
+		{
+			description:     "Correct unknown receipt",
+			method:          "PUT",
+			requestPath:     "/receipts/missing",
+			requestBody:     `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [{"shortDescription": "Dasani", "price": "1.40"}], "total": "1.40"}`,
+			expectedStatus:  http.StatusNotFound,
+			expectedBalance: 109,
+		},
+		{
+			description:     "Delete receipt",
+			method:          "DELETE",
+			requestPath:     "/receipts/r1?reason=fraud",
+			expectedStatus:  http.StatusNoContent,
+			expectedBalance: 0,
+		},
+		{
+			description:     "Delete unknown receipt",
+			method:          "DELETE",
+			requestPath:     "/receipts/r1",
+			expectedStatus:  http.StatusNotFound,
+			expectedBalance: 0,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			request := httptest.NewRequest(testCase.method, testCase.requestPath, strings.NewReader(testCase.requestBody))
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, request)
+
+			// Check for expected status code
+			if recorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d: %s", testCase.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+
+			// Check the owner's balance follows the receipt
+			if balance := receipts.Balance("user-1"); balance != testCase.expectedBalance {
+				t.Errorf("Want balance %d, got %d", testCase.expectedBalance, balance)
+			}
+
+			if testCase.method == "PUT" && recorder.Code == http.StatusOK {
+				var response models.ReceiptCorrection
+				if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+					t.Fatalf("Error parsing response: %v", err)
+				}
+				if response.Version != 2 || response.PreviousPoints != 6 || response.Points != 109 {
+					t.Errorf("Unexpected correction %+v", response)
+				}
+			}
+		})
+	}
+
+	// Both superseded versions are listed, even though the receipt was deleted
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/receipts/r1/revisions", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+	}
+	var response models.ReceiptRevisionsResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Error parsing response: %v", err)
+	}
+	revisions := response.Revisions
+	if len(revisions) != 2 || revisions[0].Version != 1 || revisions[0].Points != 6 || revisions[0].Action != models.AuditActionCorrected || *revisions[0].Reason != "OCR misread total" {
+		t.Fatalf("Unexpected revisions %+v", revisions)
+	}
+	if revisions[1].Version != 2 || revisions[1].Action != models.AuditActionDeleted || revisions[1].Receipt.Retailer != "M&M Corner Market" {
+		t.Errorf("Unexpected deleted revision %+v", revisions[1])
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/receipts/missing/revisions", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"receipt-processor/pkg/models"
+)
+
+// Machine-readable codes returned in models.ErrorResponse
+const (
+	codeInvalidJSON         = "invalid_json"
+	codeInvalidParameter    = "invalid_parameter"
+	codeValidationFailed    = "validation_failed"
+	codeUnauthorized        = "unauthorized"
+	codeForbidden           = "forbidden"
+	codeReceiptNotFound     = "receipt_not_found"
+	codeNotPending          = "receipt_not_pending"
+	codeInsufficientBalance = "insufficient_balance"
+	codeInvalidCursor       = "invalid_cursor"
+	codeRateLimited         = "rate_limited"
+	codeQueueFull           = "queue_full"
+	codeNotFound            = "not_found"
+	codeMethodNotAllowed    = "method_not_allowed"
+	codeInternalError       = "internal_error"
+)
+
+// writeError sends every non-2xx response as a JSON models.ErrorResponse
+func writeError(w http.ResponseWriter, status int, code string, messages ...string) {
+	writeJSON(w, status, models.ErrorResponse{Code: code, Errors: messages})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	// Serialize response into JSON
+	jsonResponse, err := json.Marshal(body)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"code":"` + codeInternalError + `","errors":["failed to encode response"]}`))
+		return
+	}
+
+	// Set the header and send the response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(jsonResponse)
+}
+
+// NotFound replaces the router's plain text 404
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusNotFound, codeNotFound, "no route matches "+r.URL.Path)
+}
+
+// MethodNotAllowed replaces the router's plain text 405
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusMethodNotAllowed, codeMethodNotAllowed, "method "+r.Method+" is not allowed on "+r.URL.Path)
+}
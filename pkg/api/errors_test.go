@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+
+	"github.com/gorilla/mux"
+)
+
+// newTestRouter mirrors the routing set up in cmd/main, backed by an empty store
+func newTestRouter() *mux.Router {
+	return newTestRouterWithStore(store.New())
+}
+
+func newTestRouterWithStore(receipts *store.Store) *mux.Router {
+	router := mux.NewRouter()
+	router.NotFoundHandler = http.HandlerFunc(NotFound)
+	router.MethodNotAllowedHandler = http.HandlerFunc(MethodNotAllowed)
+	RegisterRoutes(router, NewServer(receipts))
+	return router
+}
+
+func TestRouterErrorHandlers(t *testing.T) {
+	router := newTestRouter()
+
+	// Define slice of test cases
+	testCases := []struct {
+		description    string
+		method         string
+		requestPath    string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			description:    "Unknown route",
+			method:         "GET",
+			requestPath:    "/invalid",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   codeNotFound,
+		},
+		{
+			description:    "Wrong method",
+			method:         "DELETE",
+			requestPath:    "/receipts/12345/points",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedCode:   codeMethodNotAllowed,
+		},
+		{
+			description:    "Invalid JSON body",
+			method:         "POST",
+			requestPath:    "/receipts/process",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   codeInvalidJSON,
+		},
+		{
+			description:    "Unknown receipt",
+			method:         "GET",
+			requestPath:    "/receipts/does-not-exist/points",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   codeReceiptNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			request := httptest.NewRequest(testCase.method, testCase.requestPath, nil)
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, request)
+
+			// Check for expected status code
+			if recorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, recorder.Code)
+			}
+
+			if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("Expected Content-Type application/json, got %q", contentType)
+			}
+
+			// Extract error code from json response
+			var response models.ErrorResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Error parsing response: %v", err)
+			}
+			if response.Code != testCase.expectedCode {
+				t.Errorf("Want code %q, got %q", testCase.expectedCode, response.Code)
+			}
+			if len(response.Errors) == 0 {
+				t.Error("Expected at least one error message, got none")
+			}
+		})
+	}
+}
@@ -1,58 +1,73 @@
 package api
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
 
+	"receipt-processor/pkg/auth"
 	"receipt-processor/pkg/models"
-
-	"github.com/gorilla/mux"
+	"receipt-processor/pkg/store"
+	"receipt-processor/pkg/utils"
 )
 
-func GetPoints(w http.ResponseWriter, r *http.Request) {
-	// Retrieve ID from URL
-	vars := mux.Vars(r)
-	receiptID := vars["id"]
-
+func (s *Server) GetPoints(ctx context.Context, request GetPointsRequestObject) (GetPointsResponseObject, error) {
 	// Retrieve points
-	points, err := getPoints(receiptID)
+	receipt, err := s.getReceipt(ctx, request.ID)
 	// Error when ID doesn't exist
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
-		return
+		return GetPoints404JSONResponse{Code: codeReceiptNotFound, Errors: []string{err.Error()}}, nil
 	}
 
-	// Create response struct
-	response := models.GetPointsResponse{Points: points}
+	response := GetPoints200JSONResponse{Points: receipt.Points, Status: models.ReceiptStatus(receipt.Status)}
 
-	// Serialize response into JSON
-	jsonResponse, err := json.Marshal(response)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	// v2 adds how the points were calculated
+	if apiVersion(ctx) >= V2 && receipt.Status != store.StatusProcessing && receipt.Status != store.StatusFailed {
+		breakdown := pointsBreakdown(s.breakdown(ctx, receipt))
+		response.Breakdown = &breakdown
 	}
+	return response, nil
+}
 
-	// Set the header and send the response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(jsonResponse)
+// breakdown rescores a stored receipt, listing the earning caps recorded when it was credited
+func (s *Server) breakdown(ctx context.Context, receipt store.Receipt) (int64, []utils.BreakdownLine) {
+	points, breakdown := utils.CalculatePointsContext(ctx, receipt.Receipt)
+	return utils.ApplyCaps(points, breakdown, breakdownCaps(receipt.Caps))
 }
 
-func getPoints(id string) (int64, error) {
-	if points, ok := models.PointStore[id]; ok {
-		return points, nil
+// pointsBreakdown lists the lines of a breakdown from utils.CalculatePoints as rules
+func pointsBreakdown(points int64, breakdown []utils.BreakdownLine) models.PointsBreakdown {
+	converted := models.PointsBreakdown{Points: points, Rules: []models.PointsRule{}}
+	for _, line := range breakdown {
+		converted.Rules = append(converted.Rules, models.PointsRule{Points: line.Points, Description: line.Description})
 	}
+	return converted
+}
 
-	// Create and Format error response as JSON
-	errResponses := models.ErrorResponse{
-		Errors: []string{fmt.Sprintf("no receipt found for ID %s", id)},
+func (s *Server) getReceipt(ctx context.Context, id string) (store.Receipt, error) {
+	// Receipts still queued have no points yet. Workers store a receipt before they stop
+	// reporting it as processing, so checking in this order never misses one in between.
+	if owner, ok := s.processingOwner(id); ok && canAccessUser(ctx, owner) {
+		return store.Receipt{ID: id, UserID: owner, Status: store.StatusProcessing}, nil
 	}
-
-	errJSON, err := json.Marshal(errResponses)
-	if err != nil {
-		return 0, err
+	// Report other users' receipts as missing rather than forbidden so IDs can't be probed
+	if receipt, ok := s.store.Receipt(id); ok && canView(ctx, receipt) {
+		return receipt, nil
+	}
+	// Queued receipts whose scoring failed, unless they were stored before it went wrong
+	if owner, ok := s.failedOwner(id); ok && canAccessUser(ctx, owner) {
+		return store.Receipt{ID: id, UserID: owner, Status: store.StatusFailed}, nil
 	}
 
-	return 0, fmt.Errorf(string(errJSON))
+	return store.Receipt{}, fmt.Errorf("no receipt found for ID %s", id)
+}
+
+// canView limits callers signed in as a user to their own receipts
+func canView(ctx context.Context, receipt store.Receipt) bool {
+	return canAccessUser(ctx, receipt.UserID)
+}
+
+// userID is the subject of the caller's bearer token, empty for API keys and anonymous callers
+func userID(ctx context.Context) string {
+	principal, _ := auth.PrincipalFromContext(ctx)
+	return principal.Subject
 }
@@ -1,14 +1,15 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"receipt-processor/pkg/models"
-
-	"github.com/gorilla/mux"
+	"receipt-processor/pkg/store"
 )
 
 func TestGetPointsHandler(t *testing.T) {
@@ -38,7 +39,7 @@ func TestGetPointsHandler(t *testing.T) {
 			description:    "No ID provided",
 			requestPath:    "/receipts/points",
 			receiptID:      "",
-			expectedStatus: http.StatusNotFound,
+			expectedStatus: http.StatusMethodNotAllowed,
 			expectedPoints: 0,
 		},
 		{
@@ -52,19 +53,15 @@ func TestGetPointsHandler(t *testing.T) {
 
 	for _, testCase := range testCases {
 		t.Run(testCase.description, func(t *testing.T) {
+			receipts := store.New()
 			if testCase.receiptID == "12345" {
-				models.PointStore[testCase.receiptID] = testCase.expectedPoints
+				receipts.SaveReceipt(store.Receipt{ID: testCase.receiptID, Points: testCase.expectedPoints})
 			}
 
 			request := httptest.NewRequest("GET", testCase.requestPath, nil)
 			recorder := httptest.NewRecorder()
 
-			// Inject Mock Vars
-			request = mux.SetURLVars(request, map[string]string{
-				"id": testCase.receiptID,
-			})
-
-			GetPoints(recorder, request)
+			newTestRouterWithStore(receipts).ServeHTTP(recorder, request)
 
 			// Check for expected status code
 			if recorder.Code != testCase.expectedStatus {
@@ -84,3 +81,28 @@ func TestGetPointsHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestPointsBreakdownListsCaps(t *testing.T) {
+	receipts := store.New(store.WithEarningCaps(store.EarningCaps{DailyPoints: 10}))
+	receipts.SaveReceipt(store.Receipt{ID: "a", UserID: "user-1", Points: 13, Receipt: models.Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2022-01-01",
+		PurchaseTime: "13:01",
+		Items:        []models.Item{{ShortDescription: "Dasani", Price: "1.40"}},
+		Total:        "1.40",
+	}})
+	ctx := context.WithValue(context.Background(), apiVersionKey, V2)
+
+	response, _ := NewServer(receipts).GetPoints(ctx, GetPointsRequestObject{ID: "a"})
+	points, ok := response.(GetPoints200JSONResponse)
+	if !ok || points.Points != 10 || points.Breakdown == nil {
+		t.Fatalf("Unexpected response %+v", response)
+	}
+
+	// The cap's amount and reason are listed below the rules
+	rules := points.Breakdown.Rules
+	last := rules[len(rules)-1]
+	if points.Breakdown.Points != 10 || last.Points != -3 || !strings.Contains(last.Description, "daily cap of 10 points") {
+		t.Errorf("Expected the daily cap to be listed, got %+v", points.Breakdown)
+	}
+}
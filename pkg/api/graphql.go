@@ -0,0 +1,236 @@
+package api
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"receipt-processor/pkg/auth"
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+	"receipt-processor/pkg/utils"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+// maxGraphQLDepth stops queries from nesting receipts and users without end
+const maxGraphQLDepth = 6
+
+//go:embed schema.graphql
+var graphQLSchema string
+
+// GraphQL serves queries against schema.graphql, resolved from the same store as the REST handlers
+func (s *Server) GraphQL() http.Handler {
+	schema := graphql.MustParseSchema(graphQLSchema, &queryResolver{server: s}, graphql.MaxDepth(maxGraphQLDepth))
+	return &graphQLHandler{schema: schema}
+}
+
+// graphQLHandler serves queries like relay.Handler, but reports malformed requests as a JSON
+// models.ErrorResponse like the REST routes do
+type graphQLHandler struct {
+	schema *graphql.Schema
+}
+
+func (h *graphQLHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var params struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		invalidBody(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+
+	// Query errors are part of a 200 response, as the GraphQL spec describes
+	writeJSON(w, http.StatusOK, h.schema.Exec(r.Context(), params.Query, params.OperationName, params.Variables))
+}
+
+type queryResolver struct {
+	server *Server
+}
+
+func (q *queryResolver) Receipt(ctx context.Context, args struct{ ID graphql.ID }) *receiptResolver {
+	// Other users' receipts resolve to null like missing ones, as in GET /receipts/{id}/points
+	receipt, ok := q.server.store.Receipt(string(args.ID))
+	if !ok || !canView(ctx, receipt) {
+		return nil
+	}
+	return &receiptResolver{server: q.server, receipt: receipt}
+}
+
+type receiptsArgs struct {
+	Retailer      *string
+	PurchasedFrom *string
+	PurchasedTo   *string
+	MinPoints     *int32
+	MaxPoints     *int32
+	MinTotal      *string
+	MaxTotal      *string
+	Sort          *string
+	Limit         *int32
+	Cursor        *string
+}
+
+func (q *queryResolver) Receipts(ctx context.Context, args receiptsArgs) (*receiptPageResolver, error) {
+	// Validate the arguments the same way as the query parameters of GET /receipts
+	params := ListReceiptsParams{
+		Retailer:      args.Retailer,
+		PurchasedFrom: args.PurchasedFrom,
+		PurchasedTo:   args.PurchasedTo,
+		MinPoints:     int64Arg(args.MinPoints),
+		MaxPoints:     int64Arg(args.MaxPoints),
+		MinTotal:      args.MinTotal,
+		MaxTotal:      args.MaxTotal,
+		Cursor:        args.Cursor,
+	}
+	if args.Sort != nil {
+		sort := models.ListReceiptsParamsSort(*args.Sort)
+		params.Sort = &sort
+	}
+	if args.Limit != nil {
+		limit := int(*args.Limit)
+		params.Limit = &limit
+	}
+	options, validationErrors := listOptions(params)
+	if len(validationErrors) > 0 {
+		return nil, errors.New(strings.Join(validationErrors, "; "))
+	}
+
+	// Callers signed in as a user only list their own receipts
+	if principal, ok := auth.PrincipalFromContext(ctx); ok && principal.Subject != "" && !principal.HasScope(auth.ScopeAdmin) {
+		options.Filter.UserID = principal.Subject
+	}
+
+	receipts, next, err := q.server.store.ListReceipts(options)
+	if err != nil {
+		return nil, err
+	}
+	page := &receiptPageResolver{receipts: q.server.receiptResolvers(receipts)}
+	if next != "" {
+		page.nextCursor = &next
+	}
+	return page, nil
+}
+
+func (q *queryResolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	if !canAccessUser(ctx, string(args.ID)) {
+		return nil, fmt.Errorf("not allowed to access user %s", args.ID)
+	}
+	return &userResolver{server: q.server, id: string(args.ID)}, nil
+}
+
+func (s *Server) receiptResolvers(receipts []store.Receipt) []*receiptResolver {
+	resolvers := make([]*receiptResolver, len(receipts))
+	for i, receipt := range receipts {
+		resolvers[i] = &receiptResolver{server: s, receipt: receipt}
+	}
+	return resolvers
+}
+
+type receiptPageResolver struct {
+	receipts   []*receiptResolver
+	nextCursor *string
+}
+
+func (p *receiptPageResolver) Receipts() []*receiptResolver { return p.receipts }
+func (p *receiptPageResolver) NextCursor() *string          { return p.nextCursor }
+
+type receiptResolver struct {
+	server  *Server
+	receipt store.Receipt
+}
+
+func (r *receiptResolver) ID() graphql.ID       { return graphql.ID(r.receipt.ID) }
+func (r *receiptResolver) Retailer() string     { return r.receipt.Retailer }
+func (r *receiptResolver) PurchaseDate() string { return r.receipt.PurchaseDate }
+func (r *receiptResolver) PurchaseTime() string { return r.receipt.PurchaseTime }
+func (r *receiptResolver) Total() string        { return r.receipt.Total }
+func (r *receiptResolver) Points() int32        { return int32(r.receipt.Points) }
+func (r *receiptResolver) Status() string       { return string(r.receipt.Status) }
+
+func (r *receiptResolver) Items() []*itemResolver {
+	items := make([]*itemResolver, len(r.receipt.Items))
+	for i, item := range r.receipt.Items {
+		items[i] = &itemResolver{item: item}
+	}
+	return items
+}
+
+func (r *receiptResolver) Breakdown(ctx context.Context) *breakdownResolver {
+	points, breakdown := r.server.breakdown(ctx, r.receipt)
+	return &breakdownResolver{points: points, lines: breakdown}
+}
+
+func (r *receiptResolver) User() *userResolver {
+	if r.receipt.UserID == "" {
+		return nil
+	}
+	return &userResolver{server: r.server, id: r.receipt.UserID}
+}
+
+type itemResolver struct {
+	item models.Item
+}
+
+func (i *itemResolver) ShortDescription() string { return i.item.ShortDescription }
+func (i *itemResolver) Price() string            { return i.item.Price }
+
+type breakdownResolver struct {
+	points int64
+	lines  []utils.BreakdownLine
+}
+
+func (b *breakdownResolver) Points() int32 { return int32(b.points) }
+func (b *breakdownResolver) Text() string  { return utils.FormatBreakdown(b.points, b.lines) }
+
+func (b *breakdownResolver) Rules() []*rulePointsResolver {
+	rules := []*rulePointsResolver{}
+	for _, line := range b.lines {
+		rules = append(rules, &rulePointsResolver{line: line})
+	}
+	return rules
+}
+
+type rulePointsResolver struct {
+	line utils.BreakdownLine
+}
+
+func (r *rulePointsResolver) Points() int32       { return int32(r.line.Points) }
+func (r *rulePointsResolver) Description() string { return r.line.Description }
+
+type userResolver struct {
+	server *Server
+	id     string
+}
+
+func (u *userResolver) ID() graphql.ID { return graphql.ID(u.id) }
+
+// Balance matches GET /users/{id}/points, which reports the available points as the balance
+func (u *userResolver) Balance() int32 { return int32(u.totals().Available) }
+
+func (u *userResolver) Available() int32 { return int32(u.totals().Available) }
+
+func (u *userResolver) PendingExpiry() int32 { return int32(u.totals().PendingExpiry) }
+
+func (u *userResolver) Expired() int32 { return int32(u.totals().Expired) }
+
+func (u *userResolver) Receipts() []*receiptResolver {
+	return u.server.receiptResolvers(u.server.store.UserReceipts(u.id))
+}
+
+func (u *userResolver) totals() store.PointTotals {
+	return u.server.store.PointTotals(u.id, pendingExpiryWindow)
+}
+
+func int64Arg(value *int32) *int64 {
+	if value == nil {
+		return nil
+	}
+	converted := int64(*value)
+	return &converted
+}
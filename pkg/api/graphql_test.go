@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"receipt-processor/pkg/auth"
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+)
+
+func TestGraphQL(t *testing.T) {
+	receipts := store.New()
+	target := models.Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2022-01-01",
+		PurchaseTime: "13:01",
+		Items:        []models.Item{{ShortDescription: "Dasani", Price: "1.40"}},
+		Total:        "1.40",
+	}
+	receipts.SaveReceipt(store.Receipt{ID: "a", UserID: "user-1", Points: 13, Receipt: target})
+	receipts.SaveReceipt(store.Receipt{ID: "b", UserID: "user-2", Points: 13, Receipt: target})
+	handler := NewServer(receipts).GraphQL()
+
+	// Define slice of test cases
+	testCases := []struct {
+		description      string
+		query            string
+		principal        *auth.Principal
+		expectedResponse string
+	}{
+		{
+			description:      "Receipt with items and breakdown",
+			query:            `{ receipt(id: "a") { retailer items { shortDescription price } points breakdown { points rules { points description } } } }`,
+			expectedResponse: `{"data":{"receipt":{"retailer":"Target","items":[{"shortDescription":"Dasani","price":"1.40"}],"points":13,"breakdown":{"points":13,"rules":[{"points":6,"description":"retailer name (Target) has 6 alphanumeric characters"},{"points":0,"description":"1 items (0 pairs @ 5 points each)"},{"points":1,"description":"item description (Dasani) is a multiple of 3, price: 1.40"},{"points":6,"description":"purchase date day is odd"}]}}}}`,
+		},
+		{
+			description:      "User with balance and receipts",
+			query:            `{ user(id: "user-1") { id balance receipts { id status } } }`,
+			expectedResponse: `{"data":{"user":{"id":"user-1","balance":13,"receipts":[{"id":"a","status":"approved"}]}}}`,
+		},
+		{
+			description:      "Receipts are limited to the signed in user",
+			query:            `{ receipts { receipts { id user { id } } nextCursor } }`,
+			principal:        &auth.Principal{Name: "user-2", Subject: "user-2", Scopes: []auth.Scope{auth.ScopeRead}},
+			expectedResponse: `{"data":{"receipts":{"receipts":[{"id":"b","user":{"id":"user-2"}}],"nextCursor":null}}}`,
+		},
+		{
+			description:      "Other users' receipts resolve to null",
+			query:            `{ receipt(id: "a") { id } }`,
+			principal:        &auth.Principal{Name: "user-2", Subject: "user-2", Scopes: []auth.Scope{auth.ScopeRead}},
+			expectedResponse: `{"data":{"receipt":null}}`,
+		},
+		{
+			description:      "Other users are forbidden",
+			query:            `{ user(id: "user-1") { balance } }`,
+			principal:        &auth.Principal{Name: "user-2", Subject: "user-2", Scopes: []auth.Scope{auth.ScopeRead}},
+			expectedResponse: `{"errors":[{"message":"not allowed to access user user-1","path":["user"]}],"data":{"user":null}}`,
+		},
+		{
+			description:      "Invalid arguments",
+			query:            `{ receipts(limit: 500) { nextCursor } }`,
+			expectedResponse: `{"errors":[{"message":"'limit' must be between 1 and 100","path":["receipts"]}],"data":null}`,
+		},
+	}
+
+	// Iterate through test cases
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]string{"query": testCase.query})
+			request := httptest.NewRequest("POST", "/graphql", strings.NewReader(string(body)))
+			if testCase.principal != nil {
+				request = request.WithContext(auth.WithPrincipal(request.Context(), *testCase.principal))
+			}
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != http.StatusOK {
+				t.Errorf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+			}
+			if response := strings.TrimSpace(recorder.Body.String()); response != testCase.expectedResponse {
+				t.Errorf("Expected response %s, got %s", testCase.expectedResponse, response)
+			}
+		})
+	}
+}
+
+func TestGraphQLInvalidJSON(t *testing.T) {
+	handler := NewServer(store.New()).GraphQL()
+	request := httptest.NewRequest("POST", "/graphql", strings.NewReader(`{"query":`))
+	recorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(recorder, request)
+
+	// Malformed bodies are reported like those sent to the REST routes
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got %s", contentType)
+	}
+	var response models.ErrorResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.Code != codeInvalidJSON {
+		t.Errorf("Expected error code %s, got %s (%v)", codeInvalidJSON, recorder.Body.String(), err)
+	}
+}
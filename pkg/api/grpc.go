@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"strings"
+
+	"receipt-processor/pkg/auth"
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/receiptpb"
+	"receipt-processor/pkg/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// methodScopes lists the scope each gRPC method requires, like routeScopes does for REST routes
+var methodScopes = map[string]auth.Scope{
+	receiptpb.ReceiptProcessor_ProcessReceipt_FullMethodName:  auth.ScopeSubmit,
+	receiptpb.ReceiptProcessor_ProcessReceipts_FullMethodName: auth.ScopeSubmit,
+	receiptpb.ReceiptProcessor_GetPoints_FullMethodName:       auth.ScopeRead,
+	receiptpb.ReceiptProcessor_ExplainPoints_FullMethodName:   auth.ScopeRead,
+}
+
+// GRPCServer serves the ReceiptProcessor gRPC service through the same handlers as the REST API
+type GRPCServer struct {
+	receiptpb.UnimplementedReceiptProcessorServer
+	server *Server
+}
+
+func NewGRPCServer(server *Server) *GRPCServer {
+	return &GRPCServer{server: server}
+}
+
+func (g *GRPCServer) ProcessReceipt(ctx context.Context, request *receiptpb.ProcessReceiptRequest) (*receiptpb.ProcessReceiptResponse, error) {
+	result := g.process(ctx, request.GetReceipt())
+	switch {
+	case len(result.Errors) > 0:
+		return nil, status.Error(codes.InvalidArgument, strings.Join(result.Errors, "; "))
+	case result.Id == "":
+		return nil, status.Error(codes.Unavailable, "the processing queue is full, retry later")
+	}
+	return &receiptpb.ProcessReceiptResponse{Id: result.Id, Queued: result.Queued}, nil
+}
+
+func (g *GRPCServer) ProcessReceipts(ctx context.Context, request *receiptpb.ProcessReceiptsRequest) (*receiptpb.ProcessReceiptsResponse, error) {
+	body := ProcessReceiptsJSONRequestBody{Receipts: []models.Receipt{}}
+	for _, receipt := range request.GetReceipts() {
+		body.Receipts = append(body.Receipts, fromProto(receipt))
+	}
+	response, err := g.server.ProcessReceipts(ctx, ProcessReceiptsRequestObject{Body: &body})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	switch response := response.(type) {
+	case ProcessReceipts200JSONResponse:
+		results := &receiptpb.ProcessReceiptsResponse{}
+		for _, result := range response.Results {
+			converted := &receiptpb.ProcessReceiptResult{}
+			if result.ID != nil {
+				converted.Id = *result.ID
+			}
+			if result.Queued != nil {
+				converted.Queued = *result.Queued
+			}
+			if result.Errors != nil {
+				converted.Errors = *result.Errors
+			}
+			results.Results = append(results.Results, converted)
+		}
+		return results, nil
+	case ProcessReceipts400JSONResponse:
+		return nil, status.Error(codes.InvalidArgument, strings.Join(response.Errors, "; "))
+	}
+	return nil, status.Errorf(codes.Internal, "unexpected response %T", response)
+}
+
+// process runs a receipt through the REST ProcessReceipt handler. A result without an ID or
+// errors means the processing queue was full.
+func (g *GRPCServer) process(ctx context.Context, receipt *receiptpb.Receipt) *receiptpb.ProcessReceiptResult {
+	body := fromProto(receipt)
+	response, _ := g.server.ProcessReceipt(ctx, ProcessReceiptRequestObject{Body: &body})
+
+	switch response := response.(type) {
+	case ProcessReceipt200JSONResponse:
+		return &receiptpb.ProcessReceiptResult{Id: response.ID}
+	case ProcessReceipt202JSONResponse:
+		return &receiptpb.ProcessReceiptResult{Id: response.ID, Queued: true}
+	case ProcessReceipt400JSONResponse:
+		return &receiptpb.ProcessReceiptResult{Errors: response.Errors}
+	}
+	return &receiptpb.ProcessReceiptResult{}
+}
+
+func (g *GRPCServer) GetPoints(ctx context.Context, request *receiptpb.GetPointsRequest) (*receiptpb.GetPointsResponse, error) {
+	response, _ := g.server.GetPoints(ctx, GetPointsRequestObject{ID: request.GetId()})
+
+	switch response := response.(type) {
+	case GetPoints200JSONResponse:
+		return &receiptpb.GetPointsResponse{Points: response.Points, Status: string(response.Status)}, nil
+	case GetPoints404JSONResponse:
+		return nil, status.Error(codes.NotFound, strings.Join(response.Errors, "; "))
+	}
+	return nil, status.Errorf(codes.Internal, "unexpected response %T", response)
+}
+
+func (g *GRPCServer) ExplainPoints(ctx context.Context, request *receiptpb.ExplainPointsRequest) (*receiptpb.ExplainPointsResponse, error) {
+	points, breakdown, validationErrors := g.server.scoreReceipt(ctx, fromProto(request.GetReceipt()))
+	if len(validationErrors) > 0 {
+		return nil, status.Error(codes.InvalidArgument, strings.Join(validationErrors, "; "))
+	}
+	return &receiptpb.ExplainPointsResponse{Points: points, Breakdown: utils.FormatBreakdown(points, breakdown)}, nil
+}
+
+func fromProto(receipt *receiptpb.Receipt) models.Receipt {
+	converted := models.Receipt{
+		Retailer:     receipt.GetRetailer(),
+		PurchaseDate: receipt.GetPurchaseDate(),
+		PurchaseTime: receipt.GetPurchaseTime(),
+		Total:        receipt.GetTotal(),
+		Items:        []models.Item{},
+	}
+	for _, item := range receipt.GetItems() {
+		converted.Items = append(converted.Items, models.Item{ShortDescription: item.GetShortDescription(), Price: item.GetPrice()})
+	}
+	return converted
+}
+
+// GRPCRecover is the gRPC counterpart of Recover, turning a panic in a handler into a logged
+// stack trace and an Internal error instead of crashing the process
+func GRPCRecover(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (response any, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Printf("panic serving %s: %v\n%s", info.FullMethod, rec, debug.Stack())
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+
+	return handler(ctx, request)
+}
+
+// GRPCAuthenticate is the gRPC counterpart of Authenticate, reading the caller's credentials
+// from the "authorization" and "x-api-key" metadata
+func GRPCAuthenticate(keys *auth.APIKeys, tokens *auth.JWTVerifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		scope, protected := methodScopes[info.FullMethod]
+		if !protected {
+			return handler(ctx, request)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		principal, err := authenticate(firstValue(md, "authorization"), firstValue(md, apiKeyHeader), keys, tokens)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		if !principal.HasScope(scope) {
+			return nil, status.Error(codes.PermissionDenied, fmt.Sprintf("%q is missing the %q scope", principal.Name, scope))
+		}
+
+		return handler(auth.WithPrincipal(ctx, principal), request)
+	}
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"receipt-processor/pkg/auth"
+	"receipt-processor/pkg/receiptpb"
+	"receipt-processor/pkg/store"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestGRPCClient serves a GRPCServer over an in-memory connection
+func newTestGRPCClient(t *testing.T, options ...grpc.ServerOption) receiptpb.ReceiptProcessorClient {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(options...)
+	receiptpb.RegisterReceiptProcessorServer(server, NewGRPCServer(NewServer(store.New())))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return receiptpb.NewReceiptProcessorClient(conn)
+}
+
+func TestGRPCServer(t *testing.T) {
+	client := newTestGRPCClient(t)
+	ctx := context.Background()
+
+	target := &receiptpb.Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2022-01-01",
+		PurchaseTime: "13:01",
+		Items:        []*receiptpb.Item{{ShortDescription: "Dasani", Price: "1.40"}},
+		Total:        "1.40",
+	}
+	invalid := &receiptpb.Receipt{Retailer: "Target", PurchaseDate: "2022-13-01", PurchaseTime: "13:01", Total: "1.00"}
+
+	processed, err := client.ProcessReceipt(ctx, &receiptpb.ProcessReceiptRequest{Receipt: target})
+	if err != nil {
+		t.Fatalf("Error processing receipt: %v", err)
+	}
+	points, err := client.GetPoints(ctx, &receiptpb.GetPointsRequest{Id: processed.Id})
+	if err != nil {
+		t.Fatalf("Error getting points: %v", err)
+	}
+	if points.Points != 13 || points.Status != "approved" {
+		t.Errorf("Expected 13 approved points, got %d %s", points.Points, points.Status)
+	}
+
+	// Errors map to gRPC status codes
+	if _, err := client.ProcessReceipt(ctx, &receiptpb.ProcessReceiptRequest{Receipt: invalid}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected code %s, got %v", codes.InvalidArgument, err)
+	}
+	if _, err := client.GetPoints(ctx, &receiptpb.GetPointsRequest{Id: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected code %s, got %v", codes.NotFound, err)
+	}
+
+	// A batch reports each receipt's result separately
+	batch, err := client.ProcessReceipts(ctx, &receiptpb.ProcessReceiptsRequest{Receipts: []*receiptpb.Receipt{target, invalid}})
+	if err != nil {
+		t.Fatalf("Error processing batch: %v", err)
+	}
+	if len(batch.Results) != 2 || batch.Results[0].Id == "" || len(batch.Results[1].Errors) == 0 {
+		t.Errorf("Unexpected batch results %v", batch.Results)
+	}
+
+	explained, err := client.ExplainPoints(ctx, &receiptpb.ExplainPointsRequest{Receipt: target})
+	if err != nil {
+		t.Fatalf("Error explaining points: %v", err)
+	}
+	if explained.Points != 13 || !strings.Contains(explained.Breakdown, "6 points - retailer name (Target) has 6 alphanumeric characters") {
+		t.Errorf("Unexpected explanation %d %q", explained.Points, explained.Breakdown)
+	}
+}
+
+func TestGRPCAuthenticate(t *testing.T) {
+	keys, err := auth.NewAPIKeys([]auth.APIKeyConfig{
+		{Name: "dashboard", SHA256: auth.HashKey("read-key"), Scopes: []auth.Scope{auth.ScopeRead}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newTestGRPCClient(t, grpc.UnaryInterceptor(GRPCAuthenticate(keys, nil)))
+
+	// Define slice of test cases
+	testCases := []struct {
+		description  string
+		apiKey       string
+		call         func(ctx context.Context) error
+		expectedCode codes.Code
+	}{
+		{
+			description: "Missing key",
+			call: func(ctx context.Context) error {
+				_, err := client.GetPoints(ctx, &receiptpb.GetPointsRequest{Id: "missing"})
+				return err
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			description: "Unknown key",
+			apiKey:      "not-a-key",
+			call: func(ctx context.Context) error {
+				_, err := client.GetPoints(ctx, &receiptpb.GetPointsRequest{Id: "missing"})
+				return err
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			description: "Missing scope",
+			apiKey:      "read-key",
+			call: func(ctx context.Context) error {
+				_, err := client.ProcessReceipt(ctx, &receiptpb.ProcessReceiptRequest{})
+				return err
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			description: "Authorized",
+			apiKey:      "read-key",
+			call: func(ctx context.Context) error {
+				_, err := client.GetPoints(ctx, &receiptpb.GetPointsRequest{Id: "missing"})
+				return err
+			},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	// Iterate through test cases
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			ctx := context.Background()
+			if testCase.apiKey != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", testCase.apiKey)
+			}
+
+			if code := status.Code(testCase.call(ctx)); code != testCase.expectedCode {
+				t.Errorf("Expected code %s, got %s", testCase.expectedCode, code)
+			}
+		})
+	}
+}
+
+func TestGRPCRecover(t *testing.T) {
+	// Panic in the first call only, the way a bug in scoring would
+	panicked := false
+	panicking := func(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !panicked {
+			panicked = true
+			panic("scoring failed")
+		}
+		return handler(ctx, request)
+	}
+	client := newTestGRPCClient(t, grpc.ChainUnaryInterceptor(GRPCRecover, panicking))
+	ctx := context.Background()
+
+	_, err := client.GetPoints(ctx, &receiptpb.GetPointsRequest{Id: "missing"})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("Expected code %s, got %v", codes.Internal, err)
+	}
+
+	// The server keeps serving after the panic
+	_, err = client.GetPoints(ctx, &receiptpb.GetPointsRequest{Id: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected code %s, got %v", codes.NotFound, err)
+	}
+}
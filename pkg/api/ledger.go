@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+)
+
+func (s *Server) GetUserLedger(ctx context.Context, request GetUserLedgerRequestObject) (GetUserLedgerResponseObject, error) {
+	if !canAccessUser(ctx, request.ID) {
+		return GetUserLedger403JSONResponse{forbiddenUser(request.ID)}, nil
+	}
+
+	response := GetUserLedger200JSONResponse{Entries: []models.LedgerEntry{}}
+	for _, entry := range s.store.LedgerEntries(request.ID) {
+		response.Entries = append(response.Entries, ledgerEntry(entry))
+	}
+	return response, nil
+}
+
+func (s *Server) AdjustPoints(ctx context.Context, request AdjustPointsRequestObject) (AdjustPointsResponseObject, error) {
+	// Validate adjustment fields
+	var validationErrors []string
+	if request.Body.Points == 0 {
+		validationErrors = append(validationErrors, "field 'points' must not be zero")
+	}
+	if strings.TrimSpace(request.Body.Reason) == "" {
+		validationErrors = append(validationErrors, "field 'reason' is required")
+	}
+	if len(validationErrors) > 0 {
+		return AdjustPoints400JSONResponse{Code: codeValidationFailed, Errors: validationErrors}, nil
+	}
+
+	entry := s.store.Adjust(request.ID, request.Body.Points, request.Body.Reason)
+
+	s.audit(ctx, store.AuditEvent{
+		Action:       store.AuditAdjusted,
+		UserID:       entry.UserID,
+		PointsBefore: entry.Balance - entry.Points,
+		PointsAfter:  entry.Balance,
+		Reason:       entry.Reason,
+	})
+
+	return AdjustPoints201JSONResponse(ledgerEntry(entry)), nil
+}
+
+func ledgerEntry(entry store.LedgerEntry) models.LedgerEntry {
+	converted := models.LedgerEntry{
+		ID:        entry.ID,
+		Type:      models.LedgerEntryType(entry.Type),
+		Points:    entry.Points,
+		Balance:   entry.Balance,
+		CreatedAt: entry.CreatedAt,
+	}
+	if entry.ReceiptID != "" {
+		converted.ReceiptID = &entry.ReceiptID
+	}
+	if entry.Reason != "" {
+		converted.Reason = &entry.Reason
+	}
+	if !entry.ExpiresAt.IsZero() {
+		converted.ExpiresAt = &entry.ExpiresAt
+	}
+	return converted
+}
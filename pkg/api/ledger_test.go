@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"receipt-processor/pkg/auth"
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+)
+
+func TestAdjustPointsHandler(t *testing.T) {
+	receipts := store.New()
+	receipts.SaveReceipt(store.Receipt{ID: "r1", UserID: "user-1", Points: 100})
+	router := newTestRouterWithStore(receipts)
+
+	// Define slice of test cases
+	testCases := []struct {
+		description     string
+		requestBody     string
+		expectedStatus  int
+		expectedBalance int64
+	}{
+		{
+			description:     "Goodwill adjustment",
+			requestBody:     `{"points": 25, "reason": "delayed reward"}`,
+			expectedStatus:  http.StatusCreated,
+			expectedBalance: 125,
+		},
+		{
+			description:     "Negative adjustment",
+			requestBody:     `{"points": -150, "reason": "duplicate receipt"}`,
+			expectedStatus:  http.StatusCreated,
+			expectedBalance: -25,
+		},
+		{
+			description:     "Missing reason",
+			requestBody:     `{"points": 10}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedBalance: -25,
+		},
+		{
+			description:     "Zero points",
+			requestBody:     `{"points": 0, "reason": "nothing"}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedBalance: -25,
+		},
+	}
+
+	// Iterate through test cases
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			request := httptest.NewRequest("POST", "/users/user-1/adjustments", strings.NewReader(testCase.requestBody))
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, request)
+
+			// Check for expected status code
+			if recorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, recorder.Code)
+			}
+
+			// Check the balance only changes for valid adjustments
+			if balance := receipts.Balance("user-1"); balance != testCase.expectedBalance {
+				t.Errorf("Want balance %d, got %d", testCase.expectedBalance, balance)
+			}
+		})
+	}
+
+	// Adjustments are audited
+	if events := receipts.AuditLog(store.AuditFilter{Action: store.AuditAdjusted}); len(events) != 2 || events[0].PointsBefore != 100 || events[0].PointsAfter != 125 {
+		t.Errorf("Unexpected audit events %+v", events)
+	}
+}
+
+func TestGetUserLedgerHandler(t *testing.T) {
+	receipts := store.New()
+	receipts.SaveReceipt(store.Receipt{ID: "r1", UserID: "user-1", Points: 100})
+	receipts.Redeem("user-1", 60, "$5 gift card")
+	receipts.Adjust("user-1", 5, "goodwill")
+	router := newTestRouterWithStore(receipts)
+
+	// Users can only read their own ledger
+	request := httptest.NewRequest("GET", "/users/user-1/ledger", nil)
+	request = request.WithContext(auth.WithPrincipal(request.Context(), auth.Principal{Name: "user-2", Subject: "user-2", Scopes: []auth.Scope{auth.ScopeRead}}))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusForbidden {
+		t.Errorf("Expected status code %d, got %d", http.StatusForbidden, recorder.Code)
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/users/user-1/ledger", nil))
+	var response models.LedgerResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Error parsing response: %v", err)
+	}
+
+	// Entries are listed oldest first with the balance after each
+	entries := response.Entries
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %+v", entries)
+	}
+	if entries[0].Type != models.Credit || entries[0].Points != 100 || *entries[0].ReceiptID != "r1" {
+		t.Errorf("Unexpected credit %+v", entries[0])
+	}
+	if entries[1].Type != models.Debit || entries[1].Points != -60 || *entries[1].Reason != "$5 gift card" {
+		t.Errorf("Unexpected debit %+v", entries[1])
+	}
+	if entries[2].Type != models.Adjustment || entries[2].Balance != 45 {
+		t.Errorf("Unexpected adjustment %+v", entries[2])
+	}
+}
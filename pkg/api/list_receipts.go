@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"receipt-processor/pkg/auth"
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+)
+
+const defaultListLimit = 20
+
+func (s *Server) ListReceipts(ctx context.Context, request ListReceiptsRequestObject) (ListReceiptsResponseObject, error) {
+	params := request.Params
+
+	// Translate query parameters into store options
+	options, validationErrors := listOptions(params)
+	if len(validationErrors) > 0 {
+		return ListReceipts400JSONResponse{Code: codeValidationFailed, Errors: validationErrors}, nil
+	}
+
+	// Callers signed in as a user only list their own receipts
+	if principal, ok := auth.PrincipalFromContext(ctx); ok && principal.Subject != "" && !principal.HasScope(auth.ScopeAdmin) {
+		options.Filter.UserID = principal.Subject
+	}
+
+	receipts, next, err := s.store.ListReceipts(options)
+	if errors.Is(err, store.ErrInvalidCursor) {
+		return ListReceipts400JSONResponse{Code: codeInvalidCursor, Errors: []string{"cursor does not match this listing, start again without it"}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := ListReceipts200JSONResponse{Receipts: make([]models.ReceiptSummary, len(receipts))}
+	for i, receipt := range receipts {
+		response.Receipts[i] = summarize(receipt)
+	}
+	if next != "" {
+		response.NextCursor = &next
+	}
+	return response, nil
+}
+
+func listOptions(params ListReceiptsParams) (store.ListOptions, []string) {
+	var validationErrors []string
+	options := store.ListOptions{Sort: store.SortPurchaseDate, Limit: defaultListLimit}
+
+	if params.Retailer != nil {
+		options.Filter.Retailer = *params.Retailer
+	}
+	if params.PurchasedFrom != nil {
+		if _, err := time.Parse(time.DateOnly, *params.PurchasedFrom); err != nil {
+			validationErrors = append(validationErrors, "'purchasedFrom' format is invalid, expected 'YYYY-MM-DD'")
+		}
+		options.Filter.PurchasedFrom = *params.PurchasedFrom
+	}
+	if params.PurchasedTo != nil {
+		if _, err := time.Parse(time.DateOnly, *params.PurchasedTo); err != nil {
+			validationErrors = append(validationErrors, "'purchasedTo' format is invalid, expected 'YYYY-MM-DD'")
+		}
+		options.Filter.PurchasedTo = *params.PurchasedTo
+	}
+	options.Filter.MinPoints = params.MinPoints
+	options.Filter.MaxPoints = params.MaxPoints
+	if params.MinTotal != nil {
+		minTotal, err := strconv.ParseFloat(*params.MinTotal, 64)
+		if err != nil {
+			validationErrors = append(validationErrors, "'minTotal' must be a dollar amount")
+		}
+		options.Filter.MinTotal = &minTotal
+	}
+	if params.MaxTotal != nil {
+		maxTotal, err := strconv.ParseFloat(*params.MaxTotal, 64)
+		if err != nil {
+			validationErrors = append(validationErrors, "'maxTotal' must be a dollar amount")
+		}
+		options.Filter.MaxTotal = &maxTotal
+	}
+
+	if params.Sort != nil {
+		field := string(*params.Sort)
+		options.Descending = strings.HasPrefix(field, "-")
+		switch store.SortField(strings.TrimPrefix(field, "-")) {
+		case store.SortPurchaseDate:
+			options.Sort = store.SortPurchaseDate
+		case store.SortPoints:
+			options.Sort = store.SortPoints
+		default:
+			validationErrors = append(validationErrors, fmt.Sprintf("'sort' must be one of purchaseDate, -purchaseDate, points, -points, got %q", field))
+		}
+	}
+	if params.Limit != nil {
+		if *params.Limit < 1 || *params.Limit > 100 {
+			validationErrors = append(validationErrors, "'limit' must be between 1 and 100")
+		}
+		options.Limit = *params.Limit
+	}
+	if params.Cursor != nil {
+		options.Cursor = *params.Cursor
+	}
+
+	return options, validationErrors
+}
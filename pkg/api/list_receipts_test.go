@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"receipt-processor/pkg/auth"
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+)
+
+func TestListReceiptsHandler(t *testing.T) {
+	receipts := store.New()
+	receipts.SaveReceipt(store.Receipt{ID: "a", UserID: "user-1", Points: 28, Receipt: models.Receipt{Retailer: "Target", PurchaseDate: "2022-01-01", PurchaseTime: "13:01", Total: "35.35"}})
+	receipts.SaveReceipt(store.Receipt{ID: "b", UserID: "user-1", Points: 109, Receipt: models.Receipt{Retailer: "M&M Corner Market", PurchaseDate: "2022-03-20", PurchaseTime: "14:33", Total: "9.00"}})
+	receipts.SaveReceipt(store.Receipt{ID: "c", UserID: "user-2", Points: 31, Receipt: models.Receipt{Retailer: "Target", PurchaseDate: "2022-01-02", PurchaseTime: "13:13", Total: "1.25"}})
+	router := newTestRouterWithStore(receipts)
+
+	// Define slice of test cases
+	testCases := []struct {
+		description    string
+		requestPath    string
+		principal      *auth.Principal
+		expectedStatus int
+		expectedIDs    []string
+		expectedNext   bool
+	}{
+		{
+			description:    "Default listing",
+			requestPath:    "/receipts",
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"a", "c", "b"},
+		},
+		{
+			description:    "Filtered and sorted",
+			requestPath:    "/receipts?retailer=target&sort=-points",
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"c", "a"},
+		},
+		{
+			description:    "First page",
+			requestPath:    "/receipts?limit=2&minTotal=1.00&maxTotal=40",
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"a", "c"},
+			expectedNext:   true,
+		},
+		{
+			description:    "Signed in user sees only their receipts",
+			requestPath:    "/receipts",
+			principal:      &auth.Principal{Name: "user-2", Subject: "user-2"},
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{"c"},
+		},
+		{
+			description:    "Invalid date",
+			requestPath:    "/receipts?purchasedFrom=01/01/2022",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "Invalid limit",
+			requestPath:    "/receipts?limit=500",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "Invalid cursor",
+			requestPath:    "/receipts?cursor=bogus",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			request := httptest.NewRequest("GET", testCase.requestPath, nil)
+			if testCase.principal != nil {
+				request = request.WithContext(auth.WithPrincipal(request.Context(), *testCase.principal))
+			}
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, request)
+
+			// Check for expected status code
+			if recorder.Code != testCase.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d: %s", testCase.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+			if testCase.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response models.ReceiptListResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Error parsing response: %v", err)
+			}
+			if len(response.Receipts) != len(testCase.expectedIDs) {
+				t.Fatalf("Want %d receipts, got %d", len(testCase.expectedIDs), len(response.Receipts))
+			}
+			for i, receipt := range response.Receipts {
+				if receipt.ID != testCase.expectedIDs[i] {
+					t.Errorf("Want receipt %d to be %s, got %s", i, testCase.expectedIDs[i], receipt.ID)
+				}
+			}
+			if (response.NextCursor != nil) != testCase.expectedNext {
+				t.Errorf("Want next cursor %v, got %v", testCase.expectedNext, response.NextCursor)
+			}
+		})
+	}
+}
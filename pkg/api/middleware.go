@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+type contextKey string
+
+const requestIDKey contextKey = "requestID"
+
+// RequestID tags every request with an ID, reusing the client's X-Request-ID header when present
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(requestIDHeader)
+		if requestID == "" {
+			requestID = generateUniqueID()
+		}
+
+		// Echo the ID back so clients can correlate logs
+		w.Header().Set(requestIDHeader, requestID)
+
+		ctx := context.WithValue(r.Context(), requestIDKey, requestID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Recover turns a panic anywhere below it into a logged stack trace and a JSON 500
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			// Let net/http handle deliberate connection aborts
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			log.Printf("panic serving %s %s (request ID %s): %v\n%s", r.Method, r.URL.Path, RequestIDFromContext(r.Context()), rec, debug.Stack())
+
+			writeError(w, http.StatusInternalServerError, codeInternalError, "internal server error")
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
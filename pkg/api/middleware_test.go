@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"receipt-processor/pkg/models"
+)
+
+func TestRecoverMiddleware(t *testing.T) {
+	panicking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("rule exploded")
+	})
+	handler := RequestID(Recover(panicking))
+
+	request := httptest.NewRequest("POST", "/receipts/process", nil)
+	request.Header.Set(requestIDHeader, "test-request-id")
+	recorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(recorder, request)
+
+	// Check for expected status code
+	if recorder.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status code %d, got %d", http.StatusInternalServerError, recorder.Code)
+	}
+
+	// Check the request ID is echoed back
+	if id := recorder.Header().Get(requestIDHeader); id != "test-request-id" {
+		t.Errorf("Expected request ID %q, got %q", "test-request-id", id)
+	}
+
+	// Check the body uses the ErrorResponse shape
+	var response models.ErrorResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Error parsing response: %v", err)
+	}
+	if len(response.Errors) == 0 {
+		t.Error("Expected at least one error message, got none")
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var seen string
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestIDFromContext(r.Context())
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/receipts/1/points", nil))
+
+	if seen == "" {
+		t.Error("Expected a generated request ID, got empty")
+	}
+	if header := recorder.Header().Get(requestIDHeader); header != seen {
+		t.Errorf("Expected response header %q to match context ID %q", header, seen)
+	}
+}
@@ -0,0 +1,14 @@
+package api
+
+import (
+	"net/http"
+
+	"receipt-processor/pkg/openapi"
+)
+
+// OpenAPISpec serves the embedded api.yml so clients can discover the contract
+func OpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(http.StatusOK)
+	w.Write(openapi.Spec)
+}
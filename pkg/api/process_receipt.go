@@ -1,74 +1,142 @@
 package api
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
-	"net/http"
+	"strings"
 	"time"
 
+	"receipt-processor/pkg/fraud"
 	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
 	"receipt-processor/pkg/utils"
+	"receipt-processor/pkg/webhooks"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func ProcessReceipt(w http.ResponseWriter, r *http.Request) {
-	// Parse JSON request into a Receipt struct
-	var receipt models.Receipt
-	if err := json.NewDecoder(r.Body).Decode(&receipt); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+// WithLayouts sets the purchaseDate and purchaseTime formats receipts are accepted in,
+// utils.DefaultLayouts by default
+func WithLayouts(layouts utils.Layouts) Option {
+	return func(s *Server) {
+		s.layouts = layouts
 	}
+}
 
-	// Validate receipt fields
-	validationErrors := validateReceipt(receipt)
-
-	if len(validationErrors.Errors) > 0 {
-		errorResponse := models.ErrorResponse{Errors: validationErrors.Errors}
-		jsonResponse, err := json.Marshal(errorResponse)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+func (s *Server) ProcessReceipt(ctx context.Context, request ProcessReceiptRequestObject) (ProcessReceiptResponseObject, error) {
+	// Validate receipt before accepting it
+	receipt, validationErrors := s.checkReceipt(ctx, *request.Body)
+	if len(validationErrors) > 0 {
+		return ProcessReceipt400JSONResponse{Code: codeValidationFailed, Errors: validationErrors}, nil
+	}
+	original := originalOf(*request.Body)
+
+	// In asynchronous mode a worker scores the receipt while the client polls for it
+	receiptID := generateUniqueID()
+	if s.queue != nil {
+		if !s.enqueue(ctx, receiptID, receipt, original) {
+			return ProcessReceipt503JSONResponse{Code: codeQueueFull, Errors: []string{"the processing queue is full, retry later"}}, nil
 		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write(jsonResponse)
-		return
+		return ProcessReceipt202JSONResponse{ID: receiptID}, nil
 	}
 
+	s.process(ctx, receiptID, receipt, original)
+	return ProcessReceipt200JSONResponse{ID: receiptID}, nil
+}
+
+// process scores a valid, normalized receipt, checks it for fraud and stores it
+func (s *Server) process(ctx context.Context, receiptID string, receipt models.Receipt, original store.Original) {
 	// Calculate points for Receipt
-	points, breakdown := utils.CalculatePoints(receipt)
-	fmt.Print(breakdown)
+	points, breakdown := utils.CalculatePointsContext(ctx, receipt)
+
+	// Hold suspicious receipts for review instead of crediting their points
+	saved := store.Receipt{Receipt: receipt, Original: original, ID: receiptID, UserID: userID(ctx), Points: points, Status: store.StatusApproved}
+	signals := s.fraud.Detect(ctx, fraud.Submission{
+		Receipt:           receipt,
+		RecentSubmissions: s.store.SubmissionsSince(saved.UserID, time.Now().Add(-time.Hour)),
+		Now:               time.Now(),
+	})
+	if len(signals) > 0 {
+		saved.Status = store.StatusPending
+		for _, signal := range signals {
+			saved.Flags = append(saved.Flags, signal.String())
+		}
+	}
 
-	// Generate ID and save points to data store
-	receiptID := generateUniqueID()
-	setPoints(receiptID, points)
+	// Save points to data store, which applies the owner's earning caps
+	_, span := tracer.Start(ctx, "store.SaveReceipt", trace.WithAttributes(attribute.String("receipt.id", receiptID)))
+	caps := s.store.SaveReceipt(saved)
+	span.End()
+
+	points, breakdown = utils.ApplyCaps(points, breakdown, breakdownCaps(caps))
+	fmt.Print(utils.FormatBreakdown(points, breakdown))
+
+	if saved.Status == store.StatusPending {
+		s.audit(ctx, store.AuditEvent{Action: store.AuditSubmitted, ReceiptID: receiptID, UserID: saved.UserID, Reason: "held for review: " + strings.Join(saved.Flags, "; ")})
+		fmt.Printf("Held Receipt with ID: %s and Points: %d for review: %s\n", receiptID, points, strings.Join(saved.Flags, "; "))
+		return
+	}
+
+	s.audit(ctx, store.AuditEvent{Action: store.AuditSubmitted, ReceiptID: receiptID, UserID: saved.UserID, PointsAfter: points})
+
+	// Held receipts are announced once they are approved and their points credited
+	event := newReceiptEvent(saved)
+	event.Points = points
+	s.webhooks.Publish(webhooks.ReceiptProcessed, event)
+	s.feed.publish(saved.UserID, receiptStreamEvent{ID: receiptID, Retailer: receipt.Retailer, Points: points})
 
 	fmt.Printf("Successfully saved Receipt with ID: %s and Points: %d\n", receiptID, points)
+}
 
-	// Create response struct
-	response := models.PostReceiptResponse{ID: receiptID}
+// checkReceipt validates the receipt, returning it normalized along with what is wrong with it
+func (s *Server) checkReceipt(ctx context.Context, receipt models.Receipt) (models.Receipt, []string) {
+	_, span := tracer.Start(ctx, "validateReceipt")
+	defer span.End()
 
-	// Serialize response into JSON
-	jsonResponse, err := json.Marshal(response)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	normalized, validationErrors := validateReceipt(receipt, s.layouts)
+	span.SetAttributes(attribute.Int("receipt.validation_errors", len(validationErrors.Errors)))
+	return normalized, validationErrors.Errors
+}
+
+// scoreReceipt validates the receipt and, when it is valid, calculates its points and their breakdown
+func (s *Server) scoreReceipt(ctx context.Context, receipt models.Receipt) (int64, []utils.BreakdownLine, []string) {
+	receipt, validationErrors := s.checkReceipt(ctx, receipt)
+	if len(validationErrors) > 0 {
+		return 0, nil, validationErrors
 	}
 
-	// Set the header and send the response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(jsonResponse)
+	// Calculate points for Receipt
+	points, breakdown := utils.CalculatePointsContext(ctx, receipt)
+	return points, breakdown, nil
 }
 
-func setPoints(id string, points int64) string {
-	models.PointStore[id] = points
-	return id
+func breakdownCaps(caps []store.Cap) []utils.Cap {
+	converted := make([]utils.Cap, len(caps))
+	for i, c := range caps {
+		converted[i] = utils.Cap{Points: c.Points, Reason: c.Reason}
+	}
+	return converted
 }
 
-func validateReceipt(receipt models.Receipt) models.ErrorResponse {
+// originalOf records the purchase date and time as they were submitted
+func originalOf(receipt models.Receipt) store.Original {
+	return store.Original{PurchaseDate: receipt.PurchaseDate, PurchaseTime: receipt.PurchaseTime}
+}
+
+// originalPurchase reports the purchase date and time as submitted, omitted for receipts that didn't record them
+func originalPurchase(original store.Original) *models.OriginalPurchase {
+	if original == (store.Original{}) {
+		return nil
+	}
+	return &models.OriginalPurchase{PurchaseDate: original.PurchaseDate, PurchaseTime: original.PurchaseTime}
+}
+
+// validateReceipt checks the receipt's fields, returning it with the purchase date and time
+// rewritten from any of the accepted layouts into YYYY-MM-DD and HH:MM
+func validateReceipt(receipt models.Receipt, layouts utils.Layouts) (models.Receipt, models.ErrorResponse) {
 	var validationErrors []error
 	// Validate receipt fields
 	if receipt.Retailer == "" {
@@ -76,19 +144,17 @@ func validateReceipt(receipt models.Receipt) models.ErrorResponse {
 	}
 	if receipt.PurchaseDate == "" {
 		validationErrors = append(validationErrors, errors.New("field 'purchaseDate' is required"))
+	} else if date, ok := layouts.NormalizeDate(receipt.PurchaseDate); ok {
+		receipt.PurchaseDate = date
 	} else {
-		_, err := time.Parse(time.DateOnly, receipt.PurchaseDate)
-		if err != nil {
-			validationErrors = append(validationErrors, errors.New("'purchaseDate' format is invalid, expected 'YYYY-MM-DD'"))
-		}
+		validationErrors = append(validationErrors, fmt.Errorf("'purchaseDate' format is invalid, expected a date like %s", strings.Join(layouts.Dates, " or ")))
 	}
 	if receipt.PurchaseTime == "" {
 		validationErrors = append(validationErrors, errors.New("field 'purchaseTime' is required"))
+	} else if clock, ok := layouts.NormalizeTime(receipt.PurchaseTime); ok {
+		receipt.PurchaseTime = clock
 	} else {
-		_, err := time.Parse("15:04", receipt.PurchaseTime)
-		if err != nil {
-			validationErrors = append(validationErrors, errors.New("'purchaseTime' format is invalid, expected 'HH:MM'"))
-		}
+		validationErrors = append(validationErrors, fmt.Errorf("'purchaseTime' format is invalid, expected a time like %s", strings.Join(layouts.Times, " or ")))
 	}
 	if len(receipt.Items) == 0 {
 		validationErrors = append(validationErrors, errors.New("at least one item is required"))
@@ -112,7 +178,7 @@ func validateReceipt(receipt models.Receipt) models.ErrorResponse {
 		errorStrings[i] = err.Error()
 	}
 
-	return models.ErrorResponse{Errors: errorStrings}
+	return receipt, models.ErrorResponse{Errors: errorStrings}
 }
 
 func generateUniqueID() string {
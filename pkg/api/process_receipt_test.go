@@ -1,10 +1,17 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"receipt-processor/pkg/auth"
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+	"receipt-processor/pkg/utils"
 )
 
 func TestPostReceiptHandler(t *testing.T) {
@@ -36,13 +43,18 @@ func TestPostReceiptHandler(t *testing.T) {
 			request := httptest.NewRequest("POST", "/receipts/process", strings.NewReader(testCase.requestBody))
 			recorder := httptest.NewRecorder()
 
-			ProcessReceipt(recorder, request)
+			newTestRouter().ServeHTTP(recorder, request)
 
 			// Check for expected status code
 			if recorder.Code != testCase.expectedStatus {
 				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, recorder.Code)
 			}
 
+			// Check every response is JSON, including errors
+			if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("Expected Content-Type application/json, got %q", contentType)
+			}
+
 			// Check for empty response body
 			responeBody := recorder.Body.String()
 			if len(responeBody) == 0 {
@@ -51,3 +63,158 @@ func TestPostReceiptHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestSuspiciousReceiptsAreHeld(t *testing.T) {
+	receipts := store.New()
+	router := newTestRouterWithStore(receipts)
+	user := auth.Principal{Name: "user-1", Subject: "user-1", Scopes: []auth.Scope{auth.ScopeSubmit}}
+
+	// Define slice of test cases
+	testCases := []struct {
+		description     string
+		requestBody     string
+		expectedStatus  store.ReceiptStatus
+		expectedBalance int64
+	}{
+		{
+			description:     "Genuine receipt",
+			requestBody:     `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [{"shortDescription": "Dasani", "price": "1.40"}], "total": "1.40"}`,
+			expectedStatus:  store.StatusApproved,
+			expectedBalance: 13,
+		},
+		{
+			description:     "Total does not match the items",
+			requestBody:     `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [{"shortDescription": "Dasani", "price": "1.40"}], "total": "100.00"}`,
+			expectedStatus:  store.StatusPending,
+			expectedBalance: 13,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			request := httptest.NewRequest("POST", "/receipts/process", strings.NewReader(testCase.requestBody))
+			request = request.WithContext(auth.WithPrincipal(request.Context(), user))
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, request)
+
+			// Flagged receipts are still accepted
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+			}
+			var response models.PostReceiptResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Error parsing response: %v", err)
+			}
+
+			receipt, _ := receipts.Receipt(response.ID)
+			if receipt.Status != testCase.expectedStatus {
+				t.Errorf("Expected status %s, got %s", testCase.expectedStatus, receipt.Status)
+			}
+			if balance := receipts.Balance("user-1"); balance != testCase.expectedBalance {
+				t.Errorf("Want balance %d, got %d", testCase.expectedBalance, balance)
+			}
+		})
+	}
+}
+
+func TestAlternativeDateTimeFormats(t *testing.T) {
+	// Define slice of test cases
+	testCases := []struct {
+		description    string
+		layouts        utils.Layouts
+		purchaseDate   string
+		purchaseTime   string
+		expectedValid  bool
+		expectedDate   string
+		expectedTime   string
+		expectedPoints int64
+	}{
+		{
+			description:    "Canonical formats",
+			layouts:        utils.DefaultLayouts,
+			purchaseDate:   "2022-01-01",
+			purchaseTime:   "13:01",
+			expectedValid:  true,
+			expectedDate:   "2022-01-01",
+			expectedTime:   "13:01",
+			expectedPoints: 13,
+		},
+		{
+			description:    "Scanned receipt formats",
+			layouts:        utils.DefaultLayouts,
+			purchaseDate:   "01/01/2022",
+			purchaseTime:   "2:33 PM",
+			expectedValid:  true,
+			expectedDate:   "2022-01-01",
+			expectedTime:   "14:33",
+			expectedPoints: 23,
+		},
+		{
+			description:    "Time with seconds",
+			layouts:        utils.DefaultLayouts,
+			purchaseDate:   "2022-01-02",
+			purchaseTime:   "15:10:42",
+			expectedValid:  true,
+			expectedDate:   "2022-01-02",
+			expectedTime:   "15:10",
+			expectedPoints: 17,
+		},
+		{
+			description:   "Formats that are not configured",
+			layouts:       utils.Layouts{Dates: []string{utils.DateLayout}, Times: []string{utils.TimeLayout}},
+			purchaseDate:  "01/01/2022",
+			purchaseTime:  "2:33 PM",
+			expectedValid: false,
+		},
+	}
+
+	// Iterate through test cases
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			receipts := store.New()
+			server := NewServer(receipts, WithLayouts(testCase.layouts))
+			body := models.Receipt{
+				Retailer:     "Target",
+				PurchaseDate: testCase.purchaseDate,
+				PurchaseTime: testCase.purchaseTime,
+				Items:        []models.Item{{ShortDescription: "Dasani", Price: "1.40"}},
+				Total:        "1.40",
+			}
+
+			response, err := server.ProcessReceipt(context.Background(), ProcessReceiptRequestObject{Body: &body})
+			if err != nil {
+				t.Fatal(err)
+			}
+			processed, ok := response.(ProcessReceipt200JSONResponse)
+			if ok != testCase.expectedValid {
+				t.Fatalf("Expected valid %t, got %+v", testCase.expectedValid, response)
+			}
+			if !ok {
+				return
+			}
+
+			// The receipt is scored and stored in the canonical formats, keeping what was submitted
+			receipt, _ := receipts.Receipt(processed.ID)
+			if receipt.PurchaseDate != testCase.expectedDate || receipt.PurchaseTime != testCase.expectedTime {
+				t.Errorf("Expected %s %s, got %s %s", testCase.expectedDate, testCase.expectedTime, receipt.PurchaseDate, receipt.PurchaseTime)
+			}
+			if receipt.Original.PurchaseDate != testCase.purchaseDate || receipt.Original.PurchaseTime != testCase.purchaseTime {
+				t.Errorf("Expected original %s %s, got %+v", testCase.purchaseDate, testCase.purchaseTime, receipt.Original)
+			}
+			if receipt.Points != testCase.expectedPoints {
+				t.Errorf("Expected %d points, got %d", testCase.expectedPoints, receipt.Points)
+			}
+
+			// Listings report what was submitted alongside the canonical values
+			listed, err := server.ListReceipts(context.Background(), ListReceiptsRequestObject{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedOriginal := models.OriginalPurchase{PurchaseDate: testCase.purchaseDate, PurchaseTime: testCase.purchaseTime}
+			if summaries := listed.(ListReceipts200JSONResponse).Receipts; len(summaries) != 1 || summaries[0].Original == nil || *summaries[0].Original != expectedOriginal {
+				t.Errorf("Expected a listed receipt with original %+v, got %+v", expectedOriginal, summaries)
+			}
+		})
+	}
+}
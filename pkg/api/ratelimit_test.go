@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"receipt-processor/pkg/auth"
+	"receipt-processor/pkg/ratelimit"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	keys, err := auth.NewAPIKeys([]auth.APIKeyConfig{
+		{Name: "pos-terminal", SHA256: auth.HashKey("pos-key"), Scopes: []auth.Scope{auth.ScopeSubmit, auth.ScopeRead}},
+		{Name: "kiosk", SHA256: auth.HashKey("kiosk-key"), Scopes: []auth.Scope{auth.ScopeSubmit, auth.ScopeRead}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := newTestRouter()
+	router.Use(Authenticate(keys, nil), RateLimit(ratelimit.New(ratelimit.Config{Routes: map[string]ratelimit.Limit{
+		"POST /receipts/process": {RequestsPerMinute: 1, Burst: 2},
+	}})))
+
+	receipt := `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [{"shortDescription": "Dasani", "price": "1.40"}], "total": "1.40"}`
+
+	// Define slice of test cases
+	testCases := []struct {
+		description       string
+		method            string
+		requestPath       string
+		apiKey            string
+		expectedStatus    int
+		expectedRemaining string
+	}{
+		{description: "First submission", method: "POST", requestPath: "/receipts/process", apiKey: "pos-key", expectedStatus: http.StatusOK, expectedRemaining: "1"},
+		{description: "Second submission", method: "POST", requestPath: "/receipts/process", apiKey: "pos-key", expectedStatus: http.StatusOK, expectedRemaining: "0"},
+		{description: "Over the limit", method: "POST", requestPath: "/receipts/process", apiKey: "pos-key", expectedStatus: http.StatusTooManyRequests, expectedRemaining: "0"},
+		{description: "Another key", method: "POST", requestPath: "/receipts/process", apiKey: "kiosk-key", expectedStatus: http.StatusOK, expectedRemaining: "1"},
+		{description: "Unlimited route", method: "GET", requestPath: "/receipts", apiKey: "pos-key", expectedStatus: http.StatusOK},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			request := httptest.NewRequest(testCase.method, testCase.requestPath, strings.NewReader(receipt))
+			request.Header.Set(apiKeyHeader, testCase.apiKey)
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, request)
+
+			// Check for expected status code
+			if recorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, recorder.Code)
+			}
+
+			// Check the rate limit headers
+			if remaining := recorder.Header().Get("RateLimit-Remaining"); remaining != testCase.expectedRemaining {
+				t.Errorf("Expected RateLimit-Remaining %q, got %q", testCase.expectedRemaining, remaining)
+			}
+			if testCase.expectedStatus == http.StatusTooManyRequests {
+				if retryAfter := recorder.Header().Get("Retry-After"); retryAfter != "60" {
+					t.Errorf("Expected Retry-After 60, got %q", retryAfter)
+				}
+				if !strings.Contains(recorder.Body.String(), codeRateLimited) {
+					t.Errorf("Expected %s error, got %s", codeRateLimited, recorder.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitClient(t *testing.T) {
+	request := httptest.NewRequest("GET", "/receipts", nil)
+	request.RemoteAddr = "192.0.2.1:51234"
+	if client := rateLimitClient(request); client != "ip:192.0.2.1" {
+		t.Errorf("Expected ip:192.0.2.1, got %s", client)
+	}
+
+	request = request.WithContext(auth.WithPrincipal(request.Context(), auth.Principal{Name: "user-1", Subject: "user-1"}))
+	if client := rateLimitClient(request); client != "user:user-1" {
+		t.Errorf("Expected user:user-1, got %s", client)
+	}
+}
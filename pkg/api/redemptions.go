@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+	"receipt-processor/pkg/webhooks"
+)
+
+func (s *Server) RedeemPoints(ctx context.Context, request RedeemPointsRequestObject) (RedeemPointsResponseObject, error) {
+	if !canSpendFor(ctx, request.ID) {
+		return RedeemPoints403JSONResponse{forbiddenUser(request.ID)}, nil
+	}
+
+	// Validate redemption fields
+	var validationErrors []string
+	if request.Body.Points <= 0 {
+		validationErrors = append(validationErrors, "field 'points' must be a positive integer")
+	}
+	if request.Body.Reward == "" {
+		validationErrors = append(validationErrors, "field 'reward' is required")
+	}
+	if len(validationErrors) > 0 {
+		return RedeemPoints400JSONResponse{Code: codeValidationFailed, Errors: validationErrors}, nil
+	}
+
+	// Debit the points, refusing to overdraw the balance
+	entry, err := s.store.Redeem(request.ID, request.Body.Points, request.Body.Reward)
+	if errors.Is(err, store.ErrInsufficientBalance) {
+		return RedeemPoints409JSONResponse{Code: codeInsufficientBalance, Errors: []string{err.Error()}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, store.AuditEvent{
+		Action:       store.AuditRedeemed,
+		UserID:       entry.UserID,
+		PointsBefore: entry.Balance - entry.Points,
+		PointsAfter:  entry.Balance,
+		Reason:       entry.Reason,
+	})
+
+	redemption := models.Redemption{
+		ID:        entry.ID,
+		UserID:    entry.UserID,
+		Points:    -entry.Points,
+		Reward:    entry.Reason,
+		Balance:   entry.Balance,
+		CreatedAt: entry.CreatedAt,
+	}
+	s.webhooks.Publish(webhooks.PointsRedeemed, redemption)
+
+	return RedeemPoints201JSONResponse(redemption), nil
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+)
+
+func TestRedeemPointsHandler(t *testing.T) {
+	receipts := store.New()
+	receipts.SaveReceipt(store.Receipt{ID: "r1", UserID: "user-1", Points: 100})
+	router := newTestRouterWithStore(receipts)
+
+	// Define slice of test cases
+	testCases := []struct {
+		description     string
+		requestBody     string
+		expectedStatus  int
+		expectedCode    string
+		expectedBalance int64
+	}{
+		{
+			description:     "Valid redemption",
+			requestBody:     `{"points": 60, "reward": "$5 gift card"}`,
+			expectedStatus:  http.StatusCreated,
+			expectedBalance: 40,
+		},
+		{
+			description:     "Insufficient balance",
+			requestBody:     `{"points": 41, "reward": "$5 gift card"}`,
+			expectedStatus:  http.StatusConflict,
+			expectedCode:    codeInsufficientBalance,
+			expectedBalance: 40,
+		},
+		{
+			description:     "Missing reward",
+			requestBody:     `{"points": 10}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedCode:    codeValidationFailed,
+			expectedBalance: 40,
+		},
+		{
+			description:     "Negative points",
+			requestBody:     `{"points": -10, "reward": "refund"}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedCode:    codeValidationFailed,
+			expectedBalance: 40,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			request := httptest.NewRequest("POST", "/users/user-1/redemptions", strings.NewReader(testCase.requestBody))
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, request)
+
+			// Check for expected status code
+			if recorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", testCase.expectedStatus, recorder.Code)
+			}
+
+			if testCase.expectedCode != "" {
+				var response models.ErrorResponse
+				if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+					t.Fatalf("Error parsing response: %v", err)
+				}
+				if response.Code != testCase.expectedCode {
+					t.Errorf("Want code %q, got %q", testCase.expectedCode, response.Code)
+				}
+			} else {
+				var response models.Redemption
+				if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+					t.Fatalf("Error parsing response: %v", err)
+				}
+				if response.Balance != testCase.expectedBalance {
+					t.Errorf("Want balance %d in response, got %d", testCase.expectedBalance, response.Balance)
+				}
+			}
+
+			// Check the stored balance only changes on success
+			if balance := receipts.Balance("user-1"); balance != testCase.expectedBalance {
+				t.Errorf("Want balance %d, got %d", testCase.expectedBalance, balance)
+			}
+		})
+	}
+}
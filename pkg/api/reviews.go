@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+	"receipt-processor/pkg/webhooks"
+)
+
+func (s *Server) ListPendingReceipts(ctx context.Context, request ListPendingReceiptsRequestObject) (ListPendingReceiptsResponseObject, error) {
+	pending := s.store.PendingReceipts()
+
+	response := ListPendingReceipts200JSONResponse{Receipts: make([]models.PendingReceipt, len(pending))}
+	for i := range pending {
+		receipt := &pending[i]
+		response.Receipts[i] = models.PendingReceipt{
+			ID:           receipt.ID,
+			Retailer:     receipt.Retailer,
+			PurchaseDate: receipt.PurchaseDate,
+			Total:        receipt.Total,
+			Points:       receipt.Points,
+			Flags:        receipt.Flags,
+			SubmittedAt:  receipt.CreatedAt,
+		}
+		if receipt.UserID != "" {
+			response.Receipts[i].UserID = &receipt.UserID
+		}
+	}
+	return response, nil
+}
+
+func (s *Server) ApproveReceipt(ctx context.Context, request ApproveReceiptRequestObject) (ApproveReceiptResponseObject, error) {
+	approved, caps, err := s.store.ApproveReceipt(request.ID, reason(request.Params.Reason))
+	if errors.Is(err, store.ErrReceiptNotFound) {
+		return ApproveReceipt404JSONResponse{receiptNotFound(request.ID)}, nil
+	}
+	if errors.Is(err, store.ErrNotPending) {
+		return ApproveReceipt409JSONResponse{notPending(request.ID)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Anonymous receipts have no owner to credit
+	credited := approved.Points
+	if approved.UserID == "" {
+		credited = 0
+	}
+
+	// Note any earning caps that withheld points alongside the reviewer's reason
+	var reasons []string
+	if approved.ReviewReason != "" {
+		reasons = append(reasons, approved.ReviewReason)
+	}
+	for _, withheld := range caps {
+		reasons = append(reasons, withheld.Reason)
+	}
+	s.audit(ctx, store.AuditEvent{
+		Action:      store.AuditApproved,
+		ReceiptID:   approved.ID,
+		UserID:      approved.UserID,
+		PointsAfter: credited,
+		Reason:      strings.Join(reasons, "; "),
+	})
+
+	event := newReceiptEvent(approved)
+	event.Points = credited
+	s.webhooks.Publish(webhooks.ReceiptProcessed, event)
+	s.feed.publish(approved.UserID, receiptStreamEvent{ID: approved.ID, Retailer: approved.Retailer, Points: credited})
+
+	fmt.Printf("Approved Receipt with ID: %s and credited %d Points\n", approved.ID, credited)
+
+	return ApproveReceipt200JSONResponse(review(approved, credited)), nil
+}
+
+func (s *Server) RejectReceipt(ctx context.Context, request RejectReceiptRequestObject) (RejectReceiptResponseObject, error) {
+	if strings.TrimSpace(request.Params.Reason) == "" {
+		return RejectReceipt400JSONResponse{Code: codeValidationFailed, Errors: []string{"query parameter 'reason' is required"}}, nil
+	}
+
+	rejected, err := s.store.RejectReceipt(request.ID, request.Params.Reason)
+	if errors.Is(err, store.ErrReceiptNotFound) {
+		return RejectReceipt404JSONResponse{receiptNotFound(request.ID)}, nil
+	}
+	if errors.Is(err, store.ErrNotPending) {
+		return RejectReceipt409JSONResponse{notPending(request.ID)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, store.AuditEvent{
+		Action:    store.AuditRejected,
+		ReceiptID: rejected.ID,
+		UserID:    rejected.UserID,
+		Reason:    rejected.ReviewReason,
+	})
+
+	s.webhooks.Publish(webhooks.ReceiptRejected, newReceiptEvent(rejected))
+
+	fmt.Printf("Rejected Receipt with ID: %s: %s\n", rejected.ID, rejected.ReviewReason)
+
+	return RejectReceipt200JSONResponse(review(rejected, 0)), nil
+}
+
+func review(receipt store.Receipt, credited int64) models.ReceiptReview {
+	response := models.ReceiptReview{
+		ID:         receipt.ID,
+		Status:     models.ReceiptStatus(receipt.Status),
+		Points:     credited,
+		ReviewedAt: receipt.ReviewedAt,
+	}
+	if receipt.ReviewReason != "" {
+		response.Reason = &receipt.ReviewReason
+	}
+	return response
+}
+
+func notPending(id string) NotPendingJSONResponse {
+	return NotPendingJSONResponse{Code: codeNotPending, Errors: []string{fmt.Sprintf("receipt %s is not pending review", id)}}
+}
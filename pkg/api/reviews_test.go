@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+)
+
+func TestReviewHandlers(t *testing.T) {
+	receipts := store.New()
+	receipts.SaveReceipt(store.Receipt{ID: "r1", UserID: "user-1", Points: 28, Status: store.StatusPending, Flags: []string{"totalMismatch: total does not match"}})
+	receipts.SaveReceipt(store.Receipt{ID: "r2", UserID: "user-2", Points: 109, Status: store.StatusPending})
+	router := newTestRouterWithStore(receipts)
+
+	// The queue lists both receipts
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/receipts/pending", nil))
+	var queue models.PendingReceiptsResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &queue); err != nil {
+		t.Fatalf("Error parsing response: %v", err)
+	}
+	if len(queue.Receipts) != 2 || len(queue.Receipts[0].Flags) != 1 || *queue.Receipts[0].UserID != "user-1" || *queue.Receipts[1].UserID != "user-2" {
+		t.Errorf("Unexpected queue %+v", queue)
+	}
+
+	// Define slice of test cases
+	testCases := []struct {
+		description     string
+		requestPath     string
+		expectedStatus  int
+		expectedBalance int64
+	}{
+		{description: "Reject without reason", requestPath: "/receipts/r2/reject", expectedStatus: http.StatusBadRequest, expectedBalance: 0},
+		{description: "Approve", requestPath: "/receipts/r1/approve", expectedStatus: http.StatusOK, expectedBalance: 28},
+		{description: "Approve twice", requestPath: "/receipts/r1/approve", expectedStatus: http.StatusConflict, expectedBalance: 28},
+		{description: "Reject", requestPath: "/receipts/r2/reject?reason=duplicate", expectedStatus: http.StatusOK, expectedBalance: 28},
+		{description: "Unknown receipt", requestPath: "/receipts/missing/approve", expectedStatus: http.StatusNotFound, expectedBalance: 28},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, httptest.NewRequest("POST", testCase.requestPath, nil))
+
+			// Check for expected status code
+			if recorder.Code != testCase.expectedStatus {
+				t.Errorf("Expected status code %d, got %d: %s", testCase.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+
+			// Check the owner is only credited on approval
+			if balance := receipts.Balance("user-1"); balance != testCase.expectedBalance {
+				t.Errorf("Want balance %d, got %d", testCase.expectedBalance, balance)
+			}
+		})
+	}
+
+	// GetPoints reports each receipt's review outcome
+	for id, expected := range map[string]models.ReceiptStatus{"r1": models.ReceiptStatusApproved, "r2": models.ReceiptStatusRejected} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest("GET", "/receipts/"+id+"/points", nil))
+
+		var response models.GetPointsResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Error parsing response: %v", err)
+		}
+		if response.Status != expected {
+			t.Errorf("Expected %s to be %s, got %s", id, expected, response.Status)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+
+	"receipt-processor/pkg/fraud"
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+	"receipt-processor/pkg/utils"
+	"receipt-processor/pkg/webhooks"
+
+	"github.com/gorilla/mux"
+)
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.3.0 --config=oapi-codegen.yaml ../openapi/api.yml
+
+// Schema types live in pkg/models; alias them for the generated server code
+type (
+	Receipt                     = models.Receipt
+	Item                        = models.Item
+	PostReceiptResponse         = models.PostReceiptResponse
+	GetPointsResponse           = models.GetPointsResponse
+	ErrorResponse               = models.ErrorResponse
+	UserID                      = models.UserID
+	UserPointsResponse          = models.UserPointsResponse
+	UserReceiptsResponse        = models.UserReceiptsResponse
+	Redemption                  = models.Redemption
+	ReceiptListResponse         = models.ReceiptListResponse
+	ListReceiptsParams          = models.ListReceiptsParams
+	ListReceiptsParamsSort      = models.ListReceiptsParamsSort
+	ReceiptCorrection           = models.ReceiptCorrection
+	CorrectReceiptParams        = models.CorrectReceiptParams
+	DeleteReceiptParams         = models.DeleteReceiptParams
+	AuditLogResponse            = models.AuditLogResponse
+	ListAuditEventsParams       = models.ListAuditEventsParams
+	ExportAuditEventsParams     = models.ExportAuditEventsParams
+	ReceiptID                   = models.ReceiptID
+	PendingReceiptsResponse     = models.PendingReceiptsResponse
+	ReceiptReview               = models.ReceiptReview
+	ApproveReceiptParams        = models.ApproveReceiptParams
+	RejectReceiptParams         = models.RejectReceiptParams
+	ListWebhookDeliveriesParams = models.ListWebhookDeliveriesParams
+	WebhookDeliveriesResponse   = models.WebhookDeliveriesResponse
+	WebhookDeadLettersResponse  = models.WebhookDeadLettersResponse
+	StreamReceiptsParams        = models.StreamReceiptsParams
+	BatchReceiptsResponse       = models.BatchReceiptsResponse
+	PointsBreakdown             = models.PointsBreakdown
+	ReceiptRevisionsResponse    = models.ReceiptRevisionsResponse
+	LedgerEntry                 = models.LedgerEntry
+	LedgerResponse              = models.LedgerResponse
+
+	ProcessReceiptJSONRequestBody  = models.ProcessReceiptJSONRequestBody
+	RedeemPointsJSONRequestBody    = models.RedeemPointsJSONRequestBody
+	AdjustPointsJSONRequestBody    = models.AdjustPointsJSONRequestBody
+	CorrectReceiptJSONRequestBody  = models.CorrectReceiptJSONRequestBody
+	ProcessReceiptsJSONRequestBody = models.ProcessReceiptsJSONRequestBody
+	ExplainPointsJSONRequestBody   = models.ExplainPointsJSONRequestBody
+)
+
+const (
+	ApiKeyAuthScopes = models.ApiKeyAuthScopes
+	BearerAuthScopes = models.BearerAuthScopes
+)
+
+// Server implements the StrictServerInterface generated from api.yml
+type Server struct {
+	store    *store.Store
+	fraud    fraud.Rules
+	webhooks *webhooks.Dispatcher
+	layouts  utils.Layouts
+
+	// queue is nil unless receipts are processed asynchronously
+	queue      chan processJob
+	closeQueue sync.Once
+	workers    sync.WaitGroup
+	// processing maps the IDs of queued receipts to the user who submitted them
+	processing sync.Map
+	// failed maps the IDs of queued receipts that could not be scored to the user who submitted them
+	failed sync.Map
+	feed   *receiptFeed
+}
+
+var _ StrictServerInterface = (*Server)(nil)
+
+type Option func(*Server)
+
+func NewServer(store *store.Store, options ...Option) *Server {
+	s := &Server{store: store, fraud: fraud.DefaultRules, layouts: utils.DefaultLayouts, feed: newReceiptFeed()}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// RegisterRoutes mounts the generated routes for server on router, reporting
+// decoding and encoding failures in the ErrorResponse shape
+func RegisterRoutes(router *mux.Router, server StrictServerInterface) {
+	RegisterRoutesAt(router, "", server)
+}
+
+// RegisterRoutesAt mounts the generated routes under prefix, such as "/v1". Routes on a PathPrefix
+// subrouter would answer a wrong method with 404 instead of 405, so prefix each path instead.
+func RegisterRoutesAt(router *mux.Router, prefix string, server StrictServerInterface) {
+	handler := NewStrictHandlerWithOptions(server, []StrictMiddlewareFunc{endDecodeSpan}, StrictHTTPServerOptions{
+		RequestErrorHandlerFunc:  invalidBody,
+		ResponseErrorHandlerFunc: internalError,
+	})
+	HandlerWithOptions(decodeTracing{handler}, GorillaServerOptions{
+		BaseURL:          prefix,
+		BaseRouter:       router,
+		ErrorHandlerFunc: invalidParameter,
+	})
+}
+
+func invalidBody(w http.ResponseWriter, r *http.Request, err error) {
+	endDecode(r.Context(), err)
+	writeError(w, http.StatusBadRequest, codeInvalidJSON, err.Error())
+}
+
+func invalidParameter(w http.ResponseWriter, r *http.Request, err error) {
+	writeError(w, http.StatusBadRequest, codeInvalidParameter, err.Error())
+}
+
+func internalError(w http.ResponseWriter, r *http.Request, err error) {
+	writeError(w, http.StatusInternalServerError, codeInternalError, err.Error())
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// streamBufferSize is how many recent events reconnecting clients can catch up on
+	streamBufferSize = 256
+	// subscriberBufferSize is how far a client may fall behind before it is disconnected
+	subscriberBufferSize = 64
+	streamKeepAlive      = 15 * time.Second
+)
+
+// streamEvent is a credited receipt as announced on /receipts/stream
+type streamEvent struct {
+	ID     int64
+	UserID string
+	Data   receiptStreamEvent
+}
+
+// receiptStreamEvent is the data of a "receipt" event, see ReceiptStreamEvent in the spec
+type receiptStreamEvent struct {
+	ID       string `json:"id"`
+	Retailer string `json:"retailer"`
+	Points   int64  `json:"points"`
+}
+
+// receiptFeed fans credited receipts out to stream subscribers and keeps the most recent ones for resumption
+type receiptFeed struct {
+	mu          sync.Mutex
+	lastID      int64
+	recent      []streamEvent
+	subscribers map[chan streamEvent]struct{}
+	// done is closed when the server shuts down, ending every stream
+	done      chan struct{}
+	closeDone sync.Once
+}
+
+func newReceiptFeed() *receiptFeed {
+	return &receiptFeed{subscribers: make(map[chan streamEvent]struct{}), done: make(chan struct{})}
+}
+
+// CloseStreams ends every open /receipts/stream response. Register it with
+// http.Server.RegisterOnShutdown, since Shutdown waits for streams that never finish on their own.
+func (s *Server) CloseStreams() {
+	s.feed.closeDone.Do(func() { close(s.feed.done) })
+}
+
+func (f *receiptFeed) publish(userID string, data receiptStreamEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastID++
+	event := streamEvent{ID: f.lastID, UserID: userID, Data: data}
+	f.recent = append(f.recent, event)
+	if len(f.recent) > streamBufferSize {
+		f.recent = f.recent[len(f.recent)-streamBufferSize:]
+	}
+
+	for subscriber := range f.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			// Drop clients that stopped reading, they can resume with Last-Event-ID
+			delete(f.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+// subscribe returns the buffered events after lastEventID and a channel of the events that follow
+func (f *receiptFeed) subscribe(lastEventID int64) ([]streamEvent, chan streamEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var missed []streamEvent
+	for _, event := range f.recent {
+		if event.ID > lastEventID {
+			missed = append(missed, event)
+		}
+	}
+
+	subscriber := make(chan streamEvent, subscriberBufferSize)
+	f.subscribers[subscriber] = struct{}{}
+	return missed, subscriber
+}
+
+func (f *receiptFeed) unsubscribe(subscriber chan streamEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.subscribers[subscriber]; ok {
+		delete(f.subscribers, subscriber)
+		close(subscriber)
+	}
+}
+
+func (s *Server) StreamReceipts(ctx context.Context, request StreamReceiptsRequestObject) (StreamReceiptsResponseObject, error) {
+	// Without a usable Last-Event-ID only new receipts are sent
+	lastEventID := s.feed.lastEventID()
+	if request.Params.LastEventID != nil {
+		if id, err := strconv.ParseInt(*request.Params.LastEventID, 10, 64); err == nil {
+			lastEventID = id
+		}
+	}
+	return receiptStream{ctx: ctx, feed: s.feed, lastEventID: lastEventID}, nil
+}
+
+func (f *receiptFeed) lastEventID() int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.lastID
+}
+
+// receiptStream writes the feed as server-sent events until the client goes away or the server shuts down
+type receiptStream struct {
+	ctx         context.Context
+	feed        *receiptFeed
+	lastEventID int64
+}
+
+func (r receiptStream) VisitStreamReceiptsResponse(w http.ResponseWriter) error {
+	missed, subscriber := r.feed.subscribe(r.lastEventID)
+	defer r.feed.unsubscribe(subscriber)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	controller := http.NewResponseController(w)
+	if err := controller.Flush(); err != nil {
+		return err
+	}
+
+	for _, event := range missed {
+		if err := r.write(w, event); err != nil {
+			return err
+		}
+	}
+	if err := controller.Flush(); err != nil {
+		return err
+	}
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.ctx.Done():
+			return nil
+		case <-r.feed.done:
+			return nil
+		case event, ok := <-subscriber:
+			if !ok {
+				return nil
+			}
+			if err := r.write(w, event); err != nil {
+				return err
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return err
+			}
+		}
+		if err := controller.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+func (r receiptStream) write(w http.ResponseWriter, event streamEvent) error {
+	// Callers signed in as a user only see their own receipts
+	if !canAccessUser(r.ctx, event.UserID) {
+		return nil
+	}
+
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: receipt\ndata: %s\n\n", event.ID, data)
+	return err
+}
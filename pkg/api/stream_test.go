@@ -0,0 +1,147 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"receipt-processor/pkg/auth"
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+
+	"github.com/gorilla/mux"
+)
+
+func TestStreamReceipts(t *testing.T) {
+	router := mux.NewRouter()
+	RegisterRoutes(router, NewServer(store.New()))
+
+	// Sign requests in as the user named in the X-User header
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user := r.Header.Get("X-User"); user != "" {
+			principal := auth.Principal{Name: user, Subject: user, Scopes: []auth.Scope{auth.ScopeRead, auth.ScopeSubmit}}
+			r = r.WithContext(auth.WithPrincipal(r.Context(), principal))
+		}
+		router.ServeHTTP(w, r)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	submit := func(user, retailer string) {
+		body := `{"retailer": "` + retailer + `", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [{"shortDescription": "Dasani", "price": "1.40"}], "total": "1.40"}`
+		request := httptest.NewRequest("POST", "/receipts/process", strings.NewReader(body))
+		request.Header.Set("X-User", user)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+		}
+	}
+
+	submit("user-1", "Target")
+	submit("user-2", "Walmart")
+	submit("user-1", "Costco")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	request, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/receipts/stream", nil)
+	request.Header.Set("X-User", "user-1")
+	request.Header.Set("Last-Event-ID", "1")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("Error opening stream: %v", err)
+	}
+	defer response.Body.Close()
+	if contentType := response.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("Expected content type text/event-stream, got %s", contentType)
+	}
+
+	reader := bufio.NewReader(response.Body)
+	next := func() string {
+		var lines []string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("Error reading stream: %v", err)
+			}
+			if line == "\n" {
+				return strings.Join(lines, "")
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	// The missed event for user-2 is filtered out, so resuming starts with the Costco receipt
+	if event := next(); !strings.HasPrefix(event, "id: 3\nevent: receipt\n") || !strings.Contains(event, `"retailer":"Costco"`) {
+		t.Errorf("Unexpected resumed event %q", event)
+	}
+
+	submit("user-2", "Walmart")
+	submit("user-1", "Target")
+	if event := next(); !strings.HasPrefix(event, "id: 5\n") || !strings.Contains(event, `"retailer":"Target","points":`) {
+		t.Errorf("Unexpected live event %q", event)
+	}
+}
+
+func TestStreamEndsOnShutdown(t *testing.T) {
+	receiptServer := NewServer(store.New())
+	router := mux.NewRouter()
+	RegisterRoutes(router, receiptServer)
+	server := httptest.NewUnstartedServer(router)
+	server.Config.RegisterOnShutdown(receiptServer.CloseStreams)
+	server.Start()
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/receipts/stream")
+	if err != nil {
+		t.Fatalf("Error opening stream: %v", err)
+	}
+	defer response.Body.Close()
+
+	// Shutdown waits for the stream, which must end rather than hold it until the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := server.Config.Shutdown(ctx); err != nil {
+		t.Fatalf("Error shutting down with an open stream: %v", err)
+	}
+}
+
+func TestStreamHeldReceipts(t *testing.T) {
+	server := NewServer(store.New())
+	router := mux.NewRouter()
+	RegisterRoutes(router, server)
+	user := auth.Principal{Name: "user-1", Subject: "user-1", Scopes: []auth.Scope{auth.ScopeSubmit}}
+
+	// The total doesn't match the items, so the receipt is held for review
+	request := httptest.NewRequest("POST", "/receipts/process", strings.NewReader(`{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [{"shortDescription": "Dasani", "price": "1.40"}], "total": "100.00"}`))
+	request = request.WithContext(auth.WithPrincipal(request.Context(), user))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	var response models.PostReceiptResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Error parsing response: %v", err)
+	}
+
+	if id := server.feed.lastEventID(); id != 0 {
+		t.Errorf("Expected held receipt not to be streamed, got event %d", id)
+	}
+
+	// Approving the receipt announces it with the points it was credited
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("POST", "/receipts/"+response.ID+"/approve", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	missed, subscriber := server.feed.subscribe(0)
+	server.feed.unsubscribe(subscriber)
+	expected := receiptStreamEvent{ID: response.ID, Retailer: "Target", Points: 88}
+	if len(missed) != 1 || missed[0].Data != expected {
+		t.Errorf("Expected one event %+v, got %+v", expected, missed)
+	}
+}
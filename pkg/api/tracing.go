@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("receipt-processor/pkg/api")
+
+// Trace wraps each request in a server span named after its route template
+func Trace(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Continue any trace started by the caller
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		route := r.URL.Path
+		if current := mux.CurrentRoute(r); current != nil {
+			if template, err := current.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+
+		ctx, span := tracer.Start(ctx, r.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("http.route", route),
+				attribute.String("request.id", RequestIDFromContext(ctx)),
+			),
+		)
+		defer span.End()
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		span.SetAttributes(attribute.Int("http.response.status_code", recorder.status))
+		if recorder.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(recorder.status))
+		}
+	})
+}
+
+// decodeTracing times how long the generated handler takes to decode a receipt. The span is
+// carried in the context without becoming its current span, so later spans stay under the request.
+type decodeTracing struct {
+	ServerInterface
+}
+
+type decodeSpanKey struct{}
+
+func (d decodeTracing) ProcessReceipt(w http.ResponseWriter, r *http.Request) {
+	_, span := tracer.Start(r.Context(), "decodeReceipt")
+	defer span.End()
+	d.ServerInterface.ProcessReceipt(w, r.WithContext(context.WithValue(r.Context(), decodeSpanKey{}, span)))
+}
+
+// endDecodeSpan is a strict middleware that ends the decode span once the body has been decoded
+func endDecodeSpan(next StrictHandlerFunc, operationID string) StrictHandlerFunc {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		endDecode(ctx, nil)
+		return next(ctx, w, r, request)
+	}
+}
+
+// endDecode ends the decode span in ctx, if there is one, recording why decoding failed
+func endDecode(ctx context.Context, err error) {
+	span, ok := ctx.Value(decodeSpanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid JSON body")
+	}
+	span.End()
+}
+
+// statusRecorder remembers the status code written by the wrapped handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, so streaming responses can flush
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTraceSpans(t *testing.T) {
+	// Record spans in memory for the duration of the test
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	requestBody := `{"retailer": "Test Retailer", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [{"shortDescription": "Test Item", "price": "9.99"}], "total": "9.99"}`
+	request := httptest.NewRequest("POST", "/receipts/process", strings.NewReader(requestBody))
+	Trace(newTestRouter()).ServeHTTP(httptest.NewRecorder(), request)
+
+	ended := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		ended[span.Name()] = span
+	}
+
+	expectedSpans := []string{
+		"POST /receipts/process",
+		"decodeReceipt",
+		"validateReceipt",
+		"CalculatePoints",
+		"rule.retailerName",
+		"rule.itemDescriptions",
+		"rule.afternoonPurchase",
+		"store.SaveReceipt",
+	}
+	for _, name := range expectedSpans {
+		if ended[name] == nil {
+			t.Errorf("Expected span %q to be recorded", name)
+		}
+	}
+
+	// Decoding ends before validation starts, so both are children of the request span
+	if server := ended["POST /receipts/process"]; server != nil {
+		for _, name := range []string{"decodeReceipt", "validateReceipt"} {
+			if span := ended[name]; span != nil && span.Parent().SpanID() != server.SpanContext().SpanID() {
+				t.Errorf("Expected span %q to be a child of the request span", name)
+			}
+		}
+	}
+}
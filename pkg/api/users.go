@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"receipt-processor/pkg/auth"
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+)
+
+// Points expiring within this window are reported as pending expiry
+const pendingExpiryWindow = 30 * 24 * time.Hour
+
+func (s *Server) GetUserPoints(ctx context.Context, request GetUserPointsRequestObject) (GetUserPointsResponseObject, error) {
+	if !canAccessUser(ctx, request.ID) {
+		return GetUserPoints403JSONResponse{forbiddenUser(request.ID)}, nil
+	}
+
+	totals := s.store.PointTotals(request.ID, pendingExpiryWindow)
+	return GetUserPoints200JSONResponse{
+		UserID:        request.ID,
+		Balance:       totals.Available,
+		Available:     totals.Available,
+		PendingExpiry: totals.PendingExpiry,
+		Expired:       totals.Expired,
+	}, nil
+}
+
+func (s *Server) GetUserReceipts(ctx context.Context, request GetUserReceiptsRequestObject) (GetUserReceiptsResponseObject, error) {
+	if !canAccessUser(ctx, request.ID) {
+		return GetUserReceipts403JSONResponse{forbiddenUser(request.ID)}, nil
+	}
+
+	// Summarize each stored receipt for the history
+	receipts := s.store.UserReceipts(request.ID)
+	summaries := make([]models.ReceiptSummary, len(receipts))
+	for i, receipt := range receipts {
+		summaries[i] = summarize(receipt)
+	}
+
+	return GetUserReceipts200JSONResponse{UserID: request.ID, Receipts: summaries}, nil
+}
+
+// canAccessUser limits callers signed in as a user to their own account
+func canAccessUser(ctx context.Context, id string) bool {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || principal.Subject == "" || principal.HasScope(auth.ScopeAdmin) {
+		return true
+	}
+	return principal.Subject == id
+}
+
+// canSpendFor limits redemptions to the user's own token or an admin. Unlike canAccessUser,
+// it doesn't let API keys act for every user, since they are not signed in as anyone.
+func canSpendFor(ctx context.Context, id string) bool {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || principal.HasScope(auth.ScopeAdmin) {
+		return true
+	}
+	return principal.Subject != "" && principal.Subject == id
+}
+
+func forbiddenUser(id string) ForbiddenJSONResponse {
+	return ForbiddenJSONResponse{Code: codeForbidden, Errors: []string{fmt.Sprintf("not allowed to access user %s", id)}}
+}
+
+func summarize(receipt store.Receipt) models.ReceiptSummary {
+	return models.ReceiptSummary{
+		ID:           receipt.ID,
+		Retailer:     receipt.Retailer,
+		PurchaseDate: receipt.PurchaseDate,
+		PurchaseTime: receipt.PurchaseTime,
+		Total:        receipt.Total,
+		Points:       receipt.Points,
+		Original:     originalPurchase(receipt.Original),
+	}
+}
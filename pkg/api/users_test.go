@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"receipt-processor/pkg/auth"
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+)
+
+func TestUserEndpoints(t *testing.T) {
+	receipts := store.New()
+	receipts.SaveReceipt(store.Receipt{ID: "r1", UserID: "user-1", Points: 28, Receipt: models.Receipt{Retailer: "Target", PurchaseDate: "2022-01-01", PurchaseTime: "13:01", Total: "35.35"}})
+	receipts.SaveReceipt(store.Receipt{ID: "r2", UserID: "user-1", Points: 109, Receipt: models.Receipt{Retailer: "M&M Corner Market", PurchaseDate: "2022-03-20", PurchaseTime: "14:33", Total: "9.00"}})
+	receipts.SaveReceipt(store.Receipt{ID: "r3", UserID: "user-2", Points: 5})
+
+	router := newTestRouterWithStore(receipts)
+
+	t.Run("Balance", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest("GET", "/users/user-1/points", nil))
+
+		// Check for expected status code
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+		}
+
+		var response models.UserPointsResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Error parsing response: %v", err)
+		}
+		if response.Balance != 137 || response.Available != 137 {
+			t.Errorf("Want balance and available %d, got %d and %d", 137, response.Balance, response.Available)
+		}
+	})
+
+	t.Run("History", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest("GET", "/users/user-1/receipts", nil))
+
+		// Check for expected status code
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+		}
+
+		var response models.UserReceiptsResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Error parsing response: %v", err)
+		}
+		if len(response.Receipts) != 2 {
+			t.Fatalf("Want 2 receipts, got %d", len(response.Receipts))
+		}
+		if response.Receipts[1].Retailer != "M&M Corner Market" || response.Receipts[1].Points != 109 {
+			t.Errorf("Unexpected second receipt %+v", response.Receipts[1])
+		}
+	})
+}
+
+func TestCanViewUser(t *testing.T) {
+	testCases := []struct {
+		description string
+		principal   *auth.Principal
+		userID      string
+		expected    bool
+	}{
+		{"Unauthenticated", nil, "user-1", true},
+		{"API key", &auth.Principal{Name: "dashboard", Scopes: []auth.Scope{auth.ScopeRead}}, "user-1", true},
+		{"Own account", &auth.Principal{Name: "user-1", Subject: "user-1"}, "user-1", true},
+		{"Another account", &auth.Principal{Name: "user-2", Subject: "user-2"}, "user-1", false},
+		{"Admin user", &auth.Principal{Name: "support", Subject: "support", Scopes: []auth.Scope{auth.ScopeAdmin}}, "user-1", true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/users/"+testCase.userID+"/points", nil)
+			ctx := request.Context()
+			if testCase.principal != nil {
+				ctx = auth.WithPrincipal(ctx, *testCase.principal)
+			}
+
+			if actual := canAccessUser(ctx, testCase.userID); actual != testCase.expected {
+				t.Errorf("Expected canAccessUser to be %v, got %v", testCase.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// APIVersion is the major version of the API a request was routed to
+type APIVersion int
+
+const (
+	// V1 responses are frozen, new fields only appear in later versions
+	V1 APIVersion = 1
+	// V2 adds the points breakdown to GET /receipts/{id}/points
+	V2 APIVersion = 2
+)
+
+const apiVersionKey contextKey = "apiVersion"
+
+// versionPrefixes are the path prefixes of the versioned subrouters
+var versionPrefixes = []string{"/v1", "/v2"}
+
+// Version tags requests routed through a versioned subrouter with its version
+func Version(version APIVersion) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiVersionKey, version)))
+		})
+	}
+}
+
+// Deprecated marks responses from the unversioned aliases as deprecated, linking to the same
+// request under the successor prefix
+func Deprecated(successor string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Link", fmt.Sprintf("<%s%s>; rel=\"successor-version\"", successor, r.URL.RequestURI()))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// apiVersion is the version the request was routed to, V1 when it didn't come through a versioned route
+func apiVersion(ctx context.Context) APIVersion {
+	if version, ok := ctx.Value(apiVersionKey).(APIVersion); ok {
+		return version
+	}
+	return V1
+}
+
+// unversioned strips the version prefix from a path template so every version shares one route key
+func unversioned(template string) string {
+	for _, prefix := range versionPrefixes {
+		if rest, ok := strings.CutPrefix(template, prefix); ok && strings.HasPrefix(rest, "/") {
+			return rest
+		}
+	}
+	return template
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"receipt-processor/pkg/auth"
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+
+	"github.com/gorilla/mux"
+)
+
+// newVersionedTestRouter mounts the routes under /v1, /v2 and the deprecated unprefixed aliases as cmd/main does
+func newVersionedTestRouter(receipts *store.Store) *mux.Router {
+	server := NewServer(receipts)
+	router := mux.NewRouter()
+	router.NotFoundHandler = http.HandlerFunc(NotFound)
+	router.MethodNotAllowedHandler = http.HandlerFunc(MethodNotAllowed)
+
+	v1 := router.NewRoute().Subrouter()
+	v1.Use(Version(V1))
+	RegisterRoutesAt(v1, "/v1", server)
+	v2 := router.NewRoute().Subrouter()
+	v2.Use(Version(V2))
+	RegisterRoutesAt(v2, "/v2", server)
+	unversioned := router.NewRoute().Subrouter()
+	unversioned.Use(Version(V1), Deprecated("/v1"))
+	RegisterRoutes(unversioned, server)
+
+	router.HandleFunc("/openapi.yml", OpenAPISpec).Methods("GET")
+	return router
+}
+
+func TestVersionedRoutes(t *testing.T) {
+	receipts := store.New()
+	receipts.SaveReceipt(store.Receipt{ID: "a", Points: 13, Receipt: models.Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2022-01-01",
+		PurchaseTime: "13:01",
+		Items:        []models.Item{{ShortDescription: "Dasani", Price: "1.40"}},
+		Total:        "1.40",
+	}})
+	router := newVersionedTestRouter(receipts)
+
+	// Define slice of test cases
+	testCases := []struct {
+		description         string
+		method              string
+		requestPath         string
+		expectedStatus      int
+		expectedBreakdown   bool
+		expectedDeprecation string
+		expectedLink        string
+	}{
+		{
+			description:    "v1 points are unchanged",
+			method:         "GET",
+			requestPath:    "/v1/receipts/a/points",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			description:       "v2 points include the breakdown",
+			method:            "GET",
+			requestPath:       "/v2/receipts/a/points",
+			expectedStatus:    http.StatusOK,
+			expectedBreakdown: true,
+		},
+		{
+			description:         "Unprefixed routes are deprecated aliases of v1",
+			method:              "GET",
+			requestPath:         "/receipts/a/points",
+			expectedStatus:      http.StatusOK,
+			expectedDeprecation: "true",
+			expectedLink:        `</v1/receipts/a/points>; rel="successor-version"`,
+		},
+		{
+			description:    "Unknown versioned route",
+			method:         "GET",
+			requestPath:    "/v2/unknown",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			description:    "Wrong method on a versioned route",
+			method:         "POST",
+			requestPath:    "/v1/receipts/a/points",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			description:    "Routes registered after the aliases",
+			method:         "GET",
+			requestPath:    "/openapi.yml",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	// Iterate through test cases
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			request := httptest.NewRequest(testCase.method, testCase.requestPath, nil)
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != testCase.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d", testCase.expectedStatus, recorder.Code)
+			}
+			if deprecation := recorder.Header().Get("Deprecation"); deprecation != testCase.expectedDeprecation {
+				t.Errorf("Expected Deprecation %q, got %q", testCase.expectedDeprecation, deprecation)
+			}
+			if link := recorder.Header().Get("Link"); link != testCase.expectedLink {
+				t.Errorf("Expected Link %q, got %q", testCase.expectedLink, link)
+			}
+			if !strings.HasSuffix(testCase.requestPath, "/points") || recorder.Code != http.StatusOK {
+				return
+			}
+
+			var response models.GetPointsResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Error parsing response: %v", err)
+			}
+			if response.Points != 13 {
+				t.Errorf("Expected 13 points, got %d", response.Points)
+			}
+			if hasBreakdown := response.Breakdown != nil; hasBreakdown != testCase.expectedBreakdown {
+				t.Fatalf("Expected breakdown %t, got %+v", testCase.expectedBreakdown, response.Breakdown)
+			}
+			if response.Breakdown != nil && (response.Breakdown.Points != 13 || len(response.Breakdown.Rules) != 4) {
+				t.Errorf("Unexpected breakdown %+v", response.Breakdown)
+			}
+		})
+	}
+}
+
+func TestVersionedRouteScopes(t *testing.T) {
+	keys, err := auth.NewAPIKeys([]auth.APIKeyConfig{
+		{Name: "dashboard", SHA256: auth.HashKey("read-key"), Scopes: []auth.Scope{auth.ScopeRead}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := newVersionedTestRouter(store.New())
+	router.Use(Authenticate(keys, nil))
+
+	// Every version of a route requires the same scope
+	for _, path := range []string{"/receipts/process", "/v1/receipts/process", "/v2/receipts/process"} {
+		request := httptest.NewRequest("POST", path, strings.NewReader(`{}`))
+		request.Header.Set(apiKeyHeader, "read-key")
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, request)
+
+		if recorder.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d for %s, got %d", http.StatusForbidden, path, recorder.Code)
+		}
+	}
+}
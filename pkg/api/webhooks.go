@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+	"receipt-processor/pkg/webhooks"
+)
+
+// WithWebhooks publishes receipt and redemption events to the dispatcher's subscribers
+func WithWebhooks(dispatcher *webhooks.Dispatcher) Option {
+	return func(s *Server) {
+		s.webhooks = dispatcher
+	}
+}
+
+// receiptEvent is the data of receipt.processed and receipt.rejected events
+type receiptEvent struct {
+	ID       string              `json:"id"`
+	UserID   string              `json:"userId,omitempty"`
+	Retailer string              `json:"retailer"`
+	Points   int64               `json:"points"`
+	Status   store.ReceiptStatus `json:"status"`
+	Reason   string              `json:"reason,omitempty"`
+}
+
+func newReceiptEvent(receipt store.Receipt) receiptEvent {
+	return receiptEvent{
+		ID:       receipt.ID,
+		UserID:   receipt.UserID,
+		Retailer: receipt.Retailer,
+		Points:   receipt.Points,
+		Status:   receipt.Status,
+		Reason:   receipt.ReviewReason,
+	}
+}
+
+func (s *Server) ListWebhookDeliveries(ctx context.Context, request ListWebhookDeliveriesRequestObject) (ListWebhookDeliveriesResponseObject, error) {
+	response := ListWebhookDeliveries200JSONResponse{Deliveries: []models.WebhookDelivery{}}
+	deliveries := s.webhooks.Deliveries()
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		if request.Params.SubscriptionID != nil && delivery.SubscriptionID != *request.Params.SubscriptionID {
+			continue
+		}
+
+		item := models.WebhookDelivery{
+			EventID:        delivery.EventID,
+			EventType:      string(delivery.EventType),
+			SubscriptionID: delivery.SubscriptionID,
+			URL:            delivery.URL,
+			Attempt:        delivery.Attempt,
+			At:             delivery.At,
+		}
+		if delivery.StatusCode != 0 {
+			item.StatusCode = &delivery.StatusCode
+		}
+		if delivery.Error != "" {
+			item.Error = &delivery.Error
+		}
+		response.Deliveries = append(response.Deliveries, item)
+	}
+	return response, nil
+}
+
+func (s *Server) ListWebhookDeadLetters(ctx context.Context, request ListWebhookDeadLettersRequestObject) (ListWebhookDeadLettersResponseObject, error) {
+	response := ListWebhookDeadLetters200JSONResponse{DeadLetters: []models.WebhookDeadLetter{}}
+	for _, deadLetter := range s.webhooks.DeadLetters() {
+		// Round trip the data through JSON to get the shape subscribers would have received
+		var data map[string]interface{}
+		encoded, err := json.Marshal(deadLetter.Event.Data)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(encoded, &data); err != nil {
+			return nil, err
+		}
+
+		response.DeadLetters = append(response.DeadLetters, models.WebhookDeadLetter{
+			Event: models.WebhookEvent{
+				ID:        deadLetter.Event.ID,
+				Type:      models.WebhookEventType(deadLetter.Event.Type),
+				CreatedAt: deadLetter.Event.CreatedAt,
+				Data:      data,
+			},
+			SubscriptionID: deadLetter.SubscriptionID,
+			Attempts:       deadLetter.Attempts,
+			LastError:      deadLetter.LastError,
+			At:             deadLetter.At,
+		})
+	}
+	return response, nil
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"receipt-processor/pkg/auth"
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+	"receipt-processor/pkg/webhooks"
+
+	"github.com/gorilla/mux"
+)
+
+func TestWebhookEvents(t *testing.T) {
+	// Collect the events a partner receives
+	var mu sync.Mutex
+	var received []webhooks.Event
+	partner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !webhooks.Verify("s3cret", r.Header.Get(webhooks.TimestampHeader), body, r.Header.Get(webhooks.SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var event webhooks.Event
+		json.Unmarshal(body, &event)
+		mu.Lock()
+		received = append(received, event)
+		mu.Unlock()
+	}))
+	defer partner.Close()
+
+	dispatcher := webhooks.NewDispatcher([]webhooks.Subscription{
+		{ID: "partner", URL: partner.URL, Secret: "s3cret"},
+	}, webhooks.Options{InitialBackoff: time.Millisecond})
+	receipts := store.New()
+	receipts.SaveReceipt(store.Receipt{ID: "held", UserID: "user-1", Points: 50, Status: store.StatusPending})
+	receipts.SaveReceipt(store.Receipt{ID: "cleared", UserID: "user-1", Points: 20, Status: store.StatusPending})
+	router := mux.NewRouter()
+	RegisterRoutes(router, NewServer(receipts, WithWebhooks(dispatcher)))
+
+	user := auth.Principal{Name: "user-1", Subject: "user-1", Scopes: []auth.Scope{auth.ScopeSubmit}}
+	send := func(method, path, body string) {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request = request.WithContext(auth.WithPrincipal(request.Context(), user))
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		if recorder.Code >= 300 {
+			t.Fatalf("%s %s returned %d: %s", method, path, recorder.Code, recorder.Body.String())
+		}
+	}
+
+	send("POST", "/receipts/process", `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [{"shortDescription": "Dasani", "price": "1.40"}], "total": "1.40"}`)
+	send("POST", "/users/user-1/redemptions", `{"points": 10, "reward": "sticker"}`)
+	send("POST", "/receipts/held/reject?reason=duplicate", "")
+	// Receipts held for review are only announced once approved
+	send("POST", "/receipts/process", `{"retailer": "Target", "purchaseDate": "2022-01-01", "purchaseTime": "13:01", "items": [{"shortDescription": "Dasani", "price": "1.40"}], "total": "100.00"}`)
+	send("POST", "/receipts/cleared/approve", "")
+	dispatcher.Wait()
+
+	// Events are delivered concurrently, so they may arrive in any order
+	byType := make(map[webhooks.EventType]webhooks.Event)
+	processed := make(map[float64]map[string]any)
+	for _, event := range received {
+		byType[event.Type] = event
+		if data, _ := event.Data.(map[string]any); event.Type == webhooks.ReceiptProcessed {
+			processed[data["points"].(float64)] = data
+		}
+	}
+	if len(received) != 4 || len(byType) != 3 || len(processed) != 2 {
+		t.Fatalf("Expected two receipt.processed events and one of each other type, got %+v", received)
+	}
+	if data := processed[13]; data == nil || data["status"] != "approved" {
+		t.Errorf("Unexpected receipt.processed data %+v", data)
+	}
+	if data := processed[20]; data == nil || data["id"] != "cleared" || data["status"] != "approved" {
+		t.Errorf("Unexpected receipt.processed data for the approved receipt %+v", data)
+	}
+	if data, _ := byType[webhooks.ReceiptRejected].Data.(map[string]any); data["id"] != "held" || data["reason"] != "duplicate" {
+		t.Errorf("Unexpected receipt.rejected data %+v", data)
+	}
+	if data, _ := byType[webhooks.PointsRedeemed].Data.(map[string]any); data["points"] != float64(10) || data["balance"] != float64(3) {
+		t.Errorf("Unexpected points.redeemed data %+v", data)
+	}
+
+	// The delivery log records each attempt
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/webhooks/deliveries?subscriptionId=partner", nil))
+	var deliveries models.WebhookDeliveriesResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &deliveries); err != nil {
+		t.Fatalf("Error parsing response: %v", err)
+	}
+	if len(deliveries.Deliveries) != 4 || deliveries.Deliveries[0].StatusCode == nil || *deliveries.Deliveries[0].StatusCode != http.StatusOK {
+		t.Errorf("Unexpected deliveries %+v", deliveries)
+	}
+}
+
+func TestWebhookDeliveryLog(t *testing.T) {
+	// Fail the first delivery so it is retried
+	var attempts int
+	var mu sync.Mutex
+	partner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer partner.Close()
+
+	dispatcher := webhooks.NewDispatcher([]webhooks.Subscription{
+		{ID: "partner", URL: partner.URL, Secret: "s3cret"},
+	}, webhooks.Options{InitialBackoff: time.Millisecond})
+	router := mux.NewRouter()
+	RegisterRoutes(router, NewServer(store.New(), WithWebhooks(dispatcher)))
+
+	dispatcher.Publish(webhooks.ReceiptProcessed, map[string]any{"id": "r1"})
+	dispatcher.Wait()
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/webhooks/deliveries", nil))
+	var response models.WebhookDeliveriesResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Error parsing response: %v", err)
+	}
+
+	// Each attempt reports its own status code
+	deliveries := response.Deliveries
+	if len(deliveries) != 2 {
+		t.Fatalf("Expected 2 attempts, got %+v", deliveries)
+	}
+	for i, expectedStatus := range []int{http.StatusInternalServerError, http.StatusOK} {
+		if deliveries[i].Attempt != i+1 || deliveries[i].StatusCode == nil || *deliveries[i].StatusCode != expectedStatus {
+			t.Errorf("Expected attempt %d to return %d, got %+v", i+1, expectedStatus, deliveries[i])
+		}
+	}
+}
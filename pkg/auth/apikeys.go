@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type Scope string
+
+const (
+	ScopeSubmit Scope = "submit"
+	ScopeRead   Scope = "read"
+	ScopeAdmin  Scope = "admin"
+)
+
+// APIKeyConfig is one entry of the API keys file. Only the SHA-256 hash of the
+// key is stored, never the key itself.
+type APIKeyConfig struct {
+	Name   string  `json:"name"`
+	SHA256 string  `json:"sha256"`
+	Scopes []Scope `json:"scopes"`
+}
+
+type apiKeysFile struct {
+	APIKeys []APIKeyConfig `json:"apiKeys"`
+}
+
+// APIKeys looks up callers by the hash of the key they present
+type APIKeys struct {
+	byHash map[string]Principal
+}
+
+func NewAPIKeys(configs []APIKeyConfig) (*APIKeys, error) {
+	keys := &APIKeys{byHash: make(map[string]Principal, len(configs))}
+	for _, config := range configs {
+		hash := strings.ToLower(config.SHA256)
+		if config.Name == "" {
+			return nil, fmt.Errorf("api key with hash %q has no name", hash)
+		}
+		if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("api key %q: sha256 must be a hex encoded SHA-256 hash", config.Name)
+		}
+		for _, scope := range config.Scopes {
+			if scope != ScopeSubmit && scope != ScopeRead && scope != ScopeAdmin {
+				return nil, fmt.Errorf("api key %q: unknown scope %q", config.Name, scope)
+			}
+		}
+		if _, ok := keys.byHash[hash]; ok {
+			return nil, fmt.Errorf("api key %q: duplicate hash", config.Name)
+		}
+		keys.byHash[hash] = Principal{Name: config.Name, Scopes: config.Scopes}
+	}
+	return keys, nil
+}
+
+// LoadAPIKeys reads an API keys file of the form
+// {"apiKeys": [{"name": "pos", "sha256": "<hex>", "scopes": ["submit"]}]}
+func LoadAPIKeys(path string) (*APIKeys, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file apiKeysFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return NewAPIKeys(file.APIKeys)
+}
+
+// Authenticate returns the principal owning key, if any
+func (k *APIKeys) Authenticate(key string) (Principal, bool) {
+	principal, ok := k.byHash[HashKey(key)]
+	return principal, ok
+}
+
+// HashKey returns the hex SHA-256 hash to store in the API keys file for key
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
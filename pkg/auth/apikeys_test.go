@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadAPIKeys(t *testing.T) {
+	config := `{"apiKeys": [
+		{"name": "pos-terminal", "sha256": "` + HashKey("pos-secret") + `", "scopes": ["submit"]},
+		{"name": "support", "sha256": "` + HashKey("support-secret") + `", "scopes": ["admin"]}
+	]}`
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := LoadAPIKeys(path)
+	if err != nil {
+		t.Fatalf("Error loading keys: %v", err)
+	}
+
+	testCases := []struct {
+		description   string
+		key           string
+		expectedFound bool
+		expectedName  string
+		scope         Scope
+		expectedScope bool
+	}{
+		{"Submit key can submit", "pos-secret", true, "pos-terminal", ScopeSubmit, true},
+		{"Submit key cannot read", "pos-secret", true, "pos-terminal", ScopeRead, false},
+		{"Admin key can read", "support-secret", true, "support", ScopeRead, true},
+		{"Unknown key", "guess", false, "", ScopeRead, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			principal, found := keys.Authenticate(testCase.key)
+			if found != testCase.expectedFound {
+				t.Fatalf("Expected found to be %v, got %v", testCase.expectedFound, found)
+			}
+			if principal.Name != testCase.expectedName {
+				t.Errorf("Expected principal %q, got %q", testCase.expectedName, principal.Name)
+			}
+			if actual := principal.HasScope(testCase.scope); actual != testCase.expectedScope {
+				t.Errorf("Expected HasScope(%s) to be %v, got %v", testCase.scope, testCase.expectedScope, actual)
+			}
+		})
+	}
+}
+
+func TestNewAPIKeysRejectsInvalidConfig(t *testing.T) {
+	testCases := []struct {
+		description string
+		config      APIKeyConfig
+	}{
+		{"Missing name", APIKeyConfig{SHA256: HashKey("a"), Scopes: []Scope{ScopeRead}}},
+		{"Plain text key", APIKeyConfig{Name: "bad", SHA256: "secret", Scopes: []Scope{ScopeRead}}},
+		{"Unknown scope", APIKeyConfig{Name: "bad", SHA256: HashKey("a"), Scopes: []Scope{"write"}}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			if _, err := NewAPIKeys([]APIKeyConfig{testCase.config}); err == nil {
+				t.Error("Expected an error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+)
+
+// Unknown key IDs trigger a refetch of a remote key set at most this often
+const jwksRefreshInterval = time.Minute
+
+// Tokens without a scope claim act on the user's own receipts
+var defaultTokenScopes = []Scope{ScopeSubmit, ScopeRead}
+
+type JWTOptions struct {
+	// Issuer and Audience are checked against the iss and aud claims when set
+	Issuer   string
+	Audience string
+}
+
+// JWTVerifier checks bearer tokens issued by the gateway against a JSON Web Key Set
+type JWTVerifier struct {
+	source  string
+	options JWTOptions
+	client  *http.Client
+
+	mu        sync.RWMutex
+	keys      jose.JSONWebKeySet
+	fetchedAt time.Time
+}
+
+// NewJWTVerifier loads the key set from source, either a file path or an http(s) URL
+func NewJWTVerifier(source string, options JWTOptions) (*JWTVerifier, error) {
+	verifier := &JWTVerifier{
+		source:  source,
+		options: options,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+	if err := verifier.refresh(); err != nil {
+		return nil, err
+	}
+	return verifier, nil
+}
+
+// Verify validates the token's signature and claims and returns its subject as the principal
+func (v *JWTVerifier) Verify(token string) (Principal, error) {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return Principal{}, fmt.Errorf("malformed token: %w", err)
+	}
+	if len(parsed.Headers) != 1 {
+		return Principal{}, errors.New("token must have exactly one signature")
+	}
+
+	key, err := v.key(parsed.Headers[0].KeyID)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	var claims jwt.Claims
+	var extra struct {
+		Scope string `json:"scope"`
+	}
+	if err := parsed.Claims(key.Key, &claims, &extra); err != nil {
+		return Principal{}, fmt.Errorf("invalid token signature: %w", err)
+	}
+
+	expected := jwt.Expected{Issuer: v.options.Issuer, Time: time.Now()}
+	if v.options.Audience != "" {
+		expected.Audience = jwt.Audience{v.options.Audience}
+	}
+	if err := claims.ValidateWithLeeway(expected, jwt.DefaultLeeway); err != nil {
+		return Principal{}, fmt.Errorf("invalid token claims: %w", err)
+	}
+	if claims.Subject == "" {
+		return Principal{}, errors.New("token has no subject")
+	}
+
+	return Principal{Name: claims.Subject, Subject: claims.Subject, Scopes: parseScopes(extra.Scope)}, nil
+}
+
+func (v *JWTVerifier) key(keyID string) (jose.JSONWebKey, error) {
+	if key, ok := v.lookup(keyID); ok {
+		return key, nil
+	}
+
+	// The gateway may have rotated keys since we last fetched them
+	v.mu.RLock()
+	stale := v.isRemote() && time.Since(v.fetchedAt) > jwksRefreshInterval
+	v.mu.RUnlock()
+	if stale {
+		if err := v.refresh(); err != nil {
+			return jose.JSONWebKey{}, err
+		}
+		if key, ok := v.lookup(keyID); ok {
+			return key, nil
+		}
+	}
+
+	return jose.JSONWebKey{}, fmt.Errorf("no signing key found for key ID %q", keyID)
+}
+
+func (v *JWTVerifier) lookup(keyID string) (jose.JSONWebKey, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	// Tokens without a key ID are accepted when the set holds a single key
+	if keyID == "" && len(v.keys.Keys) == 1 {
+		return v.keys.Keys[0], true
+	}
+	for _, key := range v.keys.Key(keyID) {
+		if key.Use == "" || key.Use == "sig" {
+			return key, true
+		}
+	}
+	return jose.JSONWebKey{}, false
+}
+
+func (v *JWTVerifier) refresh() error {
+	data, err := v.read()
+	if err != nil {
+		return fmt.Errorf("loading JWKS from %s: %w", v.source, err)
+	}
+
+	var keys jose.JSONWebKeySet
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return fmt.Errorf("parsing JWKS from %s: %w", v.source, err)
+	}
+	for _, key := range keys.Keys {
+		if !key.IsPublic() {
+			return fmt.Errorf("JWKS from %s contains a private or symmetric key %q", v.source, key.KeyID)
+		}
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	v.mu.Unlock()
+	return nil
+}
+
+func (v *JWTVerifier) read() ([]byte, error) {
+	if !v.isRemote() {
+		return os.ReadFile(v.source)
+	}
+
+	response, err := v.client.Get(v.source)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", response.Status)
+	}
+	return io.ReadAll(response.Body)
+}
+
+func (v *JWTVerifier) isRemote() bool {
+	return strings.HasPrefix(v.source, "http://") || strings.HasPrefix(v.source, "https://")
+}
+
+func parseScopes(claim string) []Scope {
+	if claim == "" {
+		return defaultTokenScopes
+	}
+
+	// Ignore scopes meant for other services, such as openid
+	var scopes []Scope
+	for _, field := range strings.Fields(claim) {
+		switch scope := Scope(field); scope {
+		case ScopeSubmit, ScopeRead, ScopeAdmin:
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
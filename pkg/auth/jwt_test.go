@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+)
+
+type testKey struct {
+	private *rsa.PrivateKey
+	keyID   string
+}
+
+func newTestKey(t *testing.T, keyID string) testKey {
+	t.Helper()
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKey{private: private, keyID: keyID}
+}
+
+func (k testKey) jwks(t *testing.T) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{Key: &k.private.PublicKey, KeyID: k.keyID, Algorithm: string(jose.RS256), Use: "sig"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func (k testKey) sign(t *testing.T, claims jwt.Claims, scope string) string {
+	t.Helper()
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: k.private},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", k.keyID),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	builder := jwt.Signed(signer).Claims(claims)
+	if scope != "" {
+		builder = builder.Claims(map[string]interface{}{"scope": scope})
+	}
+	token, err := builder.CompactSerialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestJWTVerifier(t *testing.T) {
+	key := newTestKey(t, "gateway-1")
+	otherKey := newTestKey(t, "gateway-1")
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, key.jwks(t), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := NewJWTVerifier(path, JWTOptions{Issuer: "gateway", Audience: "receipts"})
+	if err != nil {
+		t.Fatalf("Error creating verifier: %v", err)
+	}
+
+	now := time.Now()
+	valid := jwt.Claims{Subject: "user-1", Issuer: "gateway", Audience: jwt.Audience{"receipts"}, Expiry: jwt.NewNumericDate(now.Add(time.Hour))}
+	expired := valid
+	expired.Expiry = jwt.NewNumericDate(now.Add(-time.Hour))
+	wrongAudience := valid
+	wrongAudience.Audience = jwt.Audience{"billing"}
+	noSubject := valid
+	noSubject.Subject = ""
+
+	testCases := []struct {
+		description     string
+		token           string
+		expectedError   bool
+		expectedSubject string
+		expectedScopes  []Scope
+	}{
+		{"Valid token", key.sign(t, valid, ""), false, "user-1", defaultTokenScopes},
+		{"Valid token with scopes", key.sign(t, valid, "openid read"), false, "user-1", []Scope{ScopeRead}},
+		{"Expired token", key.sign(t, expired, ""), true, "", nil},
+		{"Wrong audience", key.sign(t, wrongAudience, ""), true, "", nil},
+		{"Missing subject", key.sign(t, noSubject, ""), true, "", nil},
+		{"Signed by unknown key", otherKey.sign(t, valid, ""), true, "", nil},
+		{"Not a token", "abc.def", true, "", nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			principal, err := verifier.Verify(testCase.token)
+			if (err != nil) != testCase.expectedError {
+				t.Fatalf("Expected error to be %v, got %v", testCase.expectedError, err)
+			}
+			if principal.Subject != testCase.expectedSubject {
+				t.Errorf("Expected subject %q, got %q", testCase.expectedSubject, principal.Subject)
+			}
+			if len(principal.Scopes) != len(testCase.expectedScopes) {
+				t.Errorf("Expected scopes %v, got %v", testCase.expectedScopes, principal.Scopes)
+			}
+		})
+	}
+}
+
+func TestJWTVerifierRefreshesRemoteKeys(t *testing.T) {
+	oldKey := newTestKey(t, "old")
+	newKey := newTestKey(t, "new")
+
+	// Serve the old key set first, then the rotated one
+	current := oldKey.jwks(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(current)
+	}))
+	defer server.Close()
+
+	verifier, err := NewJWTVerifier(server.URL, JWTOptions{})
+	if err != nil {
+		t.Fatalf("Error creating verifier: %v", err)
+	}
+
+	current = newKey.jwks(t)
+	verifier.fetchedAt = time.Now().Add(-2 * jwksRefreshInterval)
+
+	claims := jwt.Claims{Subject: "user-2", Expiry: jwt.NewNumericDate(time.Now().Add(time.Hour))}
+	principal, err := verifier.Verify(newKey.sign(t, claims, ""))
+	if err != nil {
+		t.Fatalf("Expected rotated key to be fetched, got %v", err)
+	}
+	if principal.Subject != "user-2" {
+		t.Errorf("Expected subject %q, got %q", "user-2", principal.Subject)
+	}
+}
@@ -0,0 +1,191 @@
+// Package client is a Go client for the receipt processor's REST API
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"receipt-processor/pkg/models"
+)
+
+// Options configures a Client; the zero value talks to an unauthenticated server with the defaults below
+type Options struct {
+	// APIKey is sent as X-API-Key when set
+	APIKey string
+	// BearerToken is sent in the Authorization header when set, instead of APIKey
+	BearerToken string
+	// MaxAttempts is how many times a request is sent before its last error is returned, 4 by default
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, doubling for each one after, 500ms by default.
+	// A Retry-After header on the response takes precedence.
+	InitialBackoff time.Duration
+	HTTPClient     *http.Client
+}
+
+// Client calls the /v2 API. It is safe for concurrent use.
+type Client struct {
+	baseURL string
+	options Options
+}
+
+// Error is a response with an error status, decoded from the API's ErrorResponse
+type Error struct {
+	StatusCode int
+	models.ErrorResponse
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("receipt processor returned %d %s: %s", e.StatusCode, e.Code, strings.Join(e.Errors, "; "))
+}
+
+// New returns a client for the server at baseURL, such as "http://localhost:8080"
+func New(baseURL string, options Options) *Client {
+	if options.MaxAttempts < 1 {
+		options.MaxAttempts = 4
+	}
+	if options.InitialBackoff <= 0 {
+		options.InitialBackoff = 500 * time.Millisecond
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/") + "/v2", options: options}
+}
+
+// ProcessReceipt submits a receipt and returns its ID. When the server processes receipts
+// asynchronously, GetPoints reports the receipt as processing until it is scored.
+func (c *Client) ProcessReceipt(ctx context.Context, receipt models.Receipt) (models.PostReceiptResponse, error) {
+	var response models.PostReceiptResponse
+	err := c.do(ctx, http.MethodPost, "/receipts/process", receipt, &response, false)
+	return response, err
+}
+
+// ProcessReceipts submits up to 100 receipts at once, returning a result for each in order
+func (c *Client) ProcessReceipts(ctx context.Context, receipts []models.Receipt) (models.BatchReceiptsResponse, error) {
+	var response models.BatchReceiptsResponse
+	err := c.do(ctx, http.MethodPost, "/receipts/batch", models.BatchReceiptsRequest{Receipts: receipts}, &response, false)
+	return response, err
+}
+
+// GetPoints returns a receipt's points, status and, once it is scored, their breakdown
+func (c *Client) GetPoints(ctx context.Context, id string) (models.GetPointsResponse, error) {
+	var response models.GetPointsResponse
+	err := c.do(ctx, http.MethodGet, "/receipts/"+url.PathEscape(id)+"/points", nil, &response, true)
+	return response, err
+}
+
+// ExplainPoints scores a receipt without storing it
+func (c *Client) ExplainPoints(ctx context.Context, receipt models.Receipt) (models.PointsBreakdown, error) {
+	var response models.PointsBreakdown
+	err := c.do(ctx, http.MethodPost, "/receipts/explain", receipt, &response, true)
+	return response, err
+}
+
+// do sends the request until it succeeds or can't be retried. Every request is retried when it
+// was rate limited or the processing queue was full, since the server didn't act on it. Idempotent
+// requests are also retried after connection failures and other server errors.
+func (c *Client) do(ctx context.Context, method, path string, body, result any, idempotent bool) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	backoff := c.options.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := c.send(ctx, method, path, payload, result)
+		if err == nil || attempt == c.options.MaxAttempts || !retryable(err, idempotent) || ctx.Err() != nil {
+			return err
+		}
+
+		wait := backoff
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+// send makes a single attempt, returning how long the server asked to wait before the next one
+func (c *Client) send(ctx context.Context, method, path string, payload []byte, result any) (time.Duration, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return 0, err
+	}
+	if payload != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.options.BearerToken != "":
+		request.Header.Set("Authorization", "Bearer "+c.options.BearerToken)
+	case c.options.APIKey != "":
+		request.Header.Set("X-API-Key", c.options.APIKey)
+	}
+
+	response, err := c.options.HTTPClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		apiError := &Error{StatusCode: response.StatusCode}
+		if err := json.NewDecoder(response.Body).Decode(&apiError.ErrorResponse); err != nil {
+			apiError.Errors = []string{http.StatusText(response.StatusCode)}
+		}
+		return parseRetryAfter(response.Header.Get("Retry-After")), apiError
+	}
+	return 0, json.NewDecoder(response.Body).Decode(result)
+}
+
+func retryable(err error, idempotent bool) bool {
+	// Connection failures may have happened after the server acted on the request
+	var urlError *url.Error
+	if errors.As(err, &urlError) {
+		return idempotent
+	}
+
+	var apiError *Error
+	if !errors.As(err, &apiError) {
+		return false
+	}
+
+	switch apiError.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
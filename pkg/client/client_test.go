@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"receipt-processor/pkg/api"
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/store"
+
+	"github.com/gorilla/mux"
+)
+
+var target = models.Receipt{
+	Retailer:     "Target",
+	PurchaseDate: "2022-01-01",
+	PurchaseTime: "13:01",
+	Items:        []models.Item{{ShortDescription: "Dasani", Price: "1.40"}},
+	Total:        "1.40",
+}
+
+func TestClient(t *testing.T) {
+	router := mux.NewRouter()
+	v2 := router.NewRoute().Subrouter()
+	v2.Use(api.Version(api.V2))
+	api.RegisterRoutesAt(v2, "/v2", api.NewServer(store.New()))
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	client := New(server.URL, Options{})
+	ctx := context.Background()
+
+	processed, err := client.ProcessReceipt(ctx, target)
+	if err != nil {
+		t.Fatalf("Error processing receipt: %v", err)
+	}
+	points, err := client.GetPoints(ctx, processed.ID)
+	if err != nil {
+		t.Fatalf("Error getting points: %v", err)
+	}
+	if points.Points != 13 || points.Status != models.ReceiptStatusApproved || points.Breakdown == nil || len(points.Breakdown.Rules) != 4 {
+		t.Errorf("Unexpected points %+v", points)
+	}
+
+	invalid := target
+	invalid.PurchaseDate = "2022-13-01"
+	batch, err := client.ProcessReceipts(ctx, []models.Receipt{target, invalid})
+	if err != nil {
+		t.Fatalf("Error processing batch: %v", err)
+	}
+	if len(batch.Results) != 2 || batch.Results[0].ID == nil || batch.Results[1].Errors == nil {
+		t.Errorf("Unexpected batch results %+v", batch.Results)
+	}
+
+	explained, err := client.ExplainPoints(ctx, target)
+	if err != nil {
+		t.Fatalf("Error explaining points: %v", err)
+	}
+	if explained.Points != 13 {
+		t.Errorf("Expected 13 points, got %d", explained.Points)
+	}
+
+	// Error responses decode into *Error
+	_, err = client.GetPoints(ctx, "missing")
+	var apiError *Error
+	if !errors.As(err, &apiError) || apiError.StatusCode != http.StatusNotFound || apiError.Code != "receipt_not_found" {
+		t.Errorf("Expected a receipt_not_found error, got %v", err)
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	// Define slice of test cases
+	testCases := []struct {
+		description      string
+		statuses         []int
+		retryAfter       string
+		call             func(client *Client) error
+		expectedAttempts int32
+		expectedStatus   int
+	}{
+		{
+			description:      "Rate limited requests are retried",
+			statuses:         []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			call:             func(client *Client) error { _, err := client.ProcessReceipt(context.Background(), target); return err },
+			expectedAttempts: 3,
+		},
+		{
+			description:      "Retry-After is honored",
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusOK},
+			retryAfter:       "1",
+			call:             func(client *Client) error { _, err := client.ProcessReceipt(context.Background(), target); return err },
+			expectedAttempts: 2,
+		},
+		{
+			description:      "Server errors are retried for idempotent calls",
+			statuses:         []int{http.StatusBadGateway, http.StatusOK},
+			call:             func(client *Client) error { _, err := client.GetPoints(context.Background(), "id"); return err },
+			expectedAttempts: 2,
+		},
+		{
+			description:      "Server errors are not retried for submissions",
+			statuses:         []int{http.StatusBadGateway, http.StatusOK},
+			call:             func(client *Client) error { _, err := client.ProcessReceipt(context.Background(), target); return err },
+			expectedAttempts: 1,
+			expectedStatus:   http.StatusBadGateway,
+		},
+		{
+			description:      "Client errors are not retried",
+			statuses:         []int{http.StatusBadRequest, http.StatusOK},
+			call:             func(client *Client) error { _, err := client.ExplainPoints(context.Background(), target); return err },
+			expectedAttempts: 1,
+			expectedStatus:   http.StatusBadRequest,
+		},
+		{
+			description:      "Attempts are limited",
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			call:             func(client *Client) error { _, err := client.GetPoints(context.Background(), "id"); return err },
+			expectedAttempts: 3,
+			expectedStatus:   http.StatusServiceUnavailable,
+		},
+	}
+
+	// Iterate through test cases
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := testCase.statuses[attempts.Add(1)-1]
+				if status != http.StatusOK && testCase.retryAfter != "" {
+					w.Header().Set("Retry-After", testCase.retryAfter)
+				}
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					w.Write([]byte(`{"id": "id", "points": 13, "status": "approved", "rules": []}`))
+				} else {
+					w.Write([]byte(`{"code": "failed", "errors": ["failed"]}`))
+				}
+			}))
+			defer server.Close()
+
+			client := New(server.URL, Options{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+			started := time.Now()
+			err := testCase.call(client)
+
+			if got := attempts.Load(); got != testCase.expectedAttempts {
+				t.Errorf("Expected %d attempts, got %d", testCase.expectedAttempts, got)
+			}
+			var apiError *Error
+			if testCase.expectedStatus == 0 && err != nil {
+				t.Errorf("Expected success, got %v", err)
+			}
+			if testCase.expectedStatus != 0 && (!errors.As(err, &apiError) || apiError.StatusCode != testCase.expectedStatus) {
+				t.Errorf("Expected status code %d, got %v", testCase.expectedStatus, err)
+			}
+			if testCase.retryAfter != "" && time.Since(started) < time.Second {
+				t.Errorf("Expected the client to wait for Retry-After, retried after %s", time.Since(started))
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"receipt-processor/pkg/models"
+	"receipt-processor/pkg/utils"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var tracer = otel.Tracer("receipt-processor/pkg/fraud")
+
+// Signal is one reason to suspect a receipt
+type Signal struct {
+	Rule   string
+	Detail string
+}
+
+func (s Signal) String() string {
+	return s.Rule + ": " + s.Detail
+}
+
+// Rules holds the thresholds past which a receipt looks suspicious
+type Rules struct {
+	// MaxItems is the most items a plausible receipt has
+	MaxItems int
+	// MaxRepeatedItems is how many identical items scoring under rule 5 a receipt may repeat
+	MaxRepeatedItems int
+	// MaxHourlySubmissions is how many receipts a user may submit within an hour
+	MaxHourlySubmissions int
+}
+
+var DefaultRules = Rules{
+	MaxItems:             100,
+	MaxRepeatedItems:     5,
+	MaxHourlySubmissions: 20,
+}
+
+// Submission is a receipt along with what is known about who submitted it
+type Submission struct {
+	Receipt models.Receipt
+	// RecentSubmissions is how many receipts the user submitted in the past hour
+	RecentSubmissions int
+	Now               time.Time
+}
+
+// Detect returns every signal the submission raises, none for a receipt that looks genuine
+func (r Rules) Detect(ctx context.Context, submission Submission) []Signal {
+	_, span := tracer.Start(ctx, "fraud.Detect")
+	defer span.End()
+
+	receipt := submission.Receipt
+	var signals []Signal
+
+	// Purchases dated after today, allowing a day for the submitter's time zone
+	if purchaseDate, err := time.Parse(time.DateOnly, receipt.PurchaseDate); err == nil {
+		if latest := submission.Now.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1); purchaseDate.After(latest) {
+			signals = append(signals, Signal{Rule: "futurePurchaseDate", Detail: fmt.Sprintf("purchase date %s is in the future", receipt.PurchaseDate)})
+		}
+	}
+
+	// Totals that differ from the sum of the item prices
+	var itemsTotal int64
+	for _, item := range receipt.Items {
+		itemsTotal += cents(item.Price)
+	}
+	if total := cents(receipt.Total); total != itemsTotal {
+		signals = append(signals, Signal{Rule: "totalMismatch", Detail: fmt.Sprintf("total %s does not match the items, which add up to %.2f", receipt.Total, float64(itemsTotal)/100)})
+	}
+
+	if r.MaxItems > 0 && len(receipt.Items) > r.MaxItems {
+		signals = append(signals, Signal{Rule: "tooManyItems", Detail: fmt.Sprintf("%d items is more than %d", len(receipt.Items), r.MaxItems)})
+	}
+
+	// Identical items padded out to collect the item description rule again and again
+	if r.MaxRepeatedItems > 0 {
+		repeats := make(map[models.Item]int)
+		var repeated []models.Item
+		for _, item := range receipt.Items {
+			if !utils.IsMultipleOf3(item.ShortDescription) {
+				continue
+			}
+			repeats[item]++
+			if repeats[item] == r.MaxRepeatedItems+1 {
+				repeated = append(repeated, item)
+			}
+		}
+		for _, item := range repeated {
+			signals = append(signals, Signal{Rule: "repeatedItems", Detail: fmt.Sprintf("%q at %s appears %d times", item.ShortDescription, item.Price, repeats[item])})
+		}
+	}
+
+	if r.MaxHourlySubmissions > 0 && submission.RecentSubmissions >= r.MaxHourlySubmissions {
+		signals = append(signals, Signal{Rule: "submissionBurst", Detail: fmt.Sprintf("%d receipts submitted in the past hour", submission.RecentSubmissions)})
+	}
+
+	span.SetAttributes(attribute.Int("fraud.signals", len(signals)))
+	return signals
+}
+
+// cents parses a dollar amount, returning -1 when it is malformed
+func cents(amount string) int64 {
+	value, err := strconv.ParseFloat(strings.TrimSpace(amount), 64)
+	if err != nil {
+		return -1
+	}
+	return int64(math.Round(value * 100))
+}
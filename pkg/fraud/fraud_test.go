@@ -0,0 +1,105 @@
+package fraud
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"receipt-processor/pkg/models"
+)
+
+func TestDetect(t *testing.T) {
+	now := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+	genuine := models.Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2024-03-01",
+		PurchaseTime: "13:01",
+		Items: []models.Item{
+			{ShortDescription: "Mountain Dew 12PK", Price: "6.49"},
+			{ShortDescription: "Emils Cheese Pizza", Price: "12.25"},
+		},
+		Total: "18.74",
+	}
+
+	repeatedItems := make([]models.Item, 6)
+	for i := range repeatedItems {
+		repeatedItems[i] = models.Item{ShortDescription: "Knorr Creamy Chicken", Price: "12.25"}
+	}
+	// Rule 5 ignores the spaces, so this scores although its trimmed length is 17
+	repeatedSpacedItems := make([]models.Item, 50)
+	for i := range repeatedSpacedItems {
+		repeatedSpacedItems[i] = models.Item{ShortDescription: "Mountain Dew 12PK", Price: "100.00"}
+	}
+	manyItems := make([]models.Item, 101)
+	for i := range manyItems {
+		manyItems[i] = models.Item{ShortDescription: "Gum", Price: "1.00"}
+	}
+
+	// Define slice of test cases
+	testCases := []struct {
+		description       string
+		receipt           func(models.Receipt) models.Receipt
+		recentSubmissions int
+		expectedRules     []string
+	}{
+		{
+			description: "Genuine receipt",
+			receipt:     func(r models.Receipt) models.Receipt { return r },
+		},
+		{
+			description:   "Purchased tomorrow is allowed for time zones",
+			receipt:       func(r models.Receipt) models.Receipt { r.PurchaseDate = "2024-03-02"; return r },
+			expectedRules: nil,
+		},
+		{
+			description:   "Future purchase date",
+			receipt:       func(r models.Receipt) models.Receipt { r.PurchaseDate = "2024-03-05"; return r },
+			expectedRules: []string{"futurePurchaseDate"},
+		},
+		{
+			description:   "Total does not match items",
+			receipt:       func(r models.Receipt) models.Receipt { r.Total = "100.00"; return r },
+			expectedRules: []string{"totalMismatch"},
+		},
+		{
+			description:   "Too many items",
+			receipt:       func(r models.Receipt) models.Receipt { r.Items, r.Total = manyItems, "101.00"; return r },
+			expectedRules: []string{"tooManyItems", "repeatedItems"},
+		},
+		{
+			description:   "Repeated items scoring under rule 5",
+			receipt:       func(r models.Receipt) models.Receipt { r.Items, r.Total = repeatedItems, "73.50"; return r },
+			expectedRules: []string{"repeatedItems"},
+		},
+		{
+			description:   "Repeated items with spaces in their description",
+			receipt:       func(r models.Receipt) models.Receipt { r.Items, r.Total = repeatedSpacedItems, "5000.00"; return r },
+			expectedRules: []string{"repeatedItems"},
+		},
+		{
+			description:       "Submission burst",
+			receipt:           func(r models.Receipt) models.Receipt { return r },
+			recentSubmissions: 20,
+			expectedRules:     []string{"submissionBurst"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			signals := DefaultRules.Detect(context.Background(), Submission{
+				Receipt:           testCase.receipt(genuine),
+				RecentSubmissions: testCase.recentSubmissions,
+				Now:               now,
+			})
+
+			if len(signals) != len(testCase.expectedRules) {
+				t.Fatalf("Expected signals %v, got %v", testCase.expectedRules, signals)
+			}
+			for i, signal := range signals {
+				if signal.Rule != testCase.expectedRules[i] {
+					t.Errorf("Expected signal %s, got %s", testCase.expectedRules[i], signal)
+				}
+			}
+		})
+	}
+}
@@ -1,28 +1,5 @@
 package models
 
-type Receipt struct {
-	Retailer     string `json:"retailer"`
-	PurchaseDate string `json:"purchaseDate"`
-	PurchaseTime string `json:"purchaseTime"`
-	Items        []Item `json:"items"`
-	Total        string `json:"total"`
-}
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.3.0 --config=oapi-codegen.yaml ../openapi/api.yml
 
-type Item struct {
-	ShortDescription string `json:"shortDescription"`
-	Price            string `json:"price"`
-}
-
-type PostReceiptResponse struct {
-	ID string `json:"id"`
-}
-
-type GetPointsResponse struct {
-	Points int64 `json:"points"`
-}
-
-type ErrorResponse struct {
-	Errors []string `json:"errors"`
-}
-
-var PointStore = make(map[string]int64)
+// Receipt, Item and the response types are generated from pkg/openapi/api.yml into models.gen.go
@@ -0,0 +1,576 @@
+// Package models provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.3.0 DO NOT EDIT.
+package models
+
+import (
+	"time"
+)
+
+const (
+	ApiKeyAuthScopes = "ApiKeyAuth.Scopes"
+	BearerAuthScopes = "BearerAuth.Scopes"
+)
+
+// Defines values for AuditAction.
+const (
+	AuditActionAdjusted     AuditAction = "adjusted"
+	AuditActionApproved     AuditAction = "approved"
+	AuditActionCorrected    AuditAction = "corrected"
+	AuditActionDeleted      AuditAction = "deleted"
+	AuditActionRecalculated AuditAction = "recalculated"
+	AuditActionRedeemed     AuditAction = "redeemed"
+	AuditActionRejected     AuditAction = "rejected"
+	AuditActionSubmitted    AuditAction = "submitted"
+)
+
+// Defines values for LedgerEntryType.
+const (
+	Adjustment LedgerEntryType = "adjustment"
+	Credit     LedgerEntryType = "credit"
+	Debit      LedgerEntryType = "debit"
+	Expiry     LedgerEntryType = "expiry"
+)
+
+// Defines values for ReceiptStatus.
+const (
+	ReceiptStatusApproved   ReceiptStatus = "approved"
+	ReceiptStatusFailed     ReceiptStatus = "failed"
+	ReceiptStatusPending    ReceiptStatus = "pending"
+	ReceiptStatusProcessing ReceiptStatus = "processing"
+	ReceiptStatusRejected   ReceiptStatus = "rejected"
+)
+
+// Defines values for WebhookEventType.
+const (
+	PointsRedeemed   WebhookEventType = "points.redeemed"
+	ReceiptProcessed WebhookEventType = "receipt.processed"
+	ReceiptRejected  WebhookEventType = "receipt.rejected"
+)
+
+// Defines values for ListReceiptsParamsSort.
+const (
+	MinusPoints       ListReceiptsParamsSort = "-points"
+	MinusPurchaseDate ListReceiptsParamsSort = "-purchaseDate"
+	Points            ListReceiptsParamsSort = "points"
+	PurchaseDate      ListReceiptsParamsSort = "purchaseDate"
+)
+
+// AdjustmentRequest defines model for AdjustmentRequest.
+type AdjustmentRequest struct {
+	// Points The points to add to the balance, negative to take them away.
+	Points int64 `json:"points"`
+
+	// Reason Why the balance is adjusted, kept in the ledger and the audit trail.
+	Reason string `json:"reason"`
+}
+
+// AuditAction defines model for AuditAction.
+type AuditAction string
+
+// AuditEvent defines model for AuditEvent.
+type AuditEvent struct {
+	Action AuditAction `json:"action"`
+
+	// Actor The token subject or API key name that performed the action, anonymous without authentication.
+	Actor string `json:"actor"`
+	ID    string `json:"id"`
+
+	// PointsAfter The receipt's points after the action, or the user's balance for redemptions.
+	PointsAfter int64 `json:"pointsAfter"`
+
+	// PointsBefore The receipt's points before the action, or the user's balance for redemptions.
+	PointsBefore int64   `json:"pointsBefore"`
+	Reason       *string `json:"reason,omitempty"`
+	ReceiptID    *string `json:"receiptId,omitempty"`
+
+	// RequestID The X-Request-ID of the request that performed the action.
+	RequestID *string   `json:"requestId,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// UserID The user whose points changed, absent for anonymous receipts.
+	UserID *string `json:"userId,omitempty"`
+}
+
+// AuditLogResponse defines model for AuditLogResponse.
+type AuditLogResponse struct {
+	Events []AuditEvent `json:"events"`
+}
+
+// BatchReceiptResult The ID assigned to a receipt, or why it was rejected
+type BatchReceiptResult struct {
+	Errors *[]string `json:"errors,omitempty"`
+	ID     *string   `json:"id,omitempty"`
+
+	// Queued Set when the receipt was queued for scoring in asynchronous mode
+	Queued *bool `json:"queued,omitempty"`
+}
+
+// BatchReceiptsRequest defines model for BatchReceiptsRequest.
+type BatchReceiptsRequest struct {
+	Receipts []Receipt `json:"receipts"`
+}
+
+// BatchReceiptsResponse defines model for BatchReceiptsResponse.
+type BatchReceiptsResponse struct {
+	Results []BatchReceiptResult `json:"results"`
+}
+
+// ErrorResponse defines model for ErrorResponse.
+type ErrorResponse struct {
+	// Code Machine-readable error code.
+	Code string `json:"code"`
+
+	// Errors Human-readable descriptions of what went wrong.
+	Errors []string `json:"errors"`
+}
+
+// GetPointsResponse defines model for GetPointsResponse.
+type GetPointsResponse struct {
+	// Breakdown How the receipt's points were calculated. Returned by /receipts/explain, and by /v2 alongside the
+	// points of a receipt once it is scored.
+	Breakdown *PointsBreakdown `json:"breakdown,omitempty"`
+
+	// Points The points the receipt scored, only credited to its owner once approved.
+	Points int64 `json:"points"`
+
+	// Status Receipts are processing while queued for scoring in asynchronous mode, and failed if scoring
+	// them went wrong. Receipts flagged by the fraud checks stay pending until an admin approves or
+	// rejects them.
+	Status ReceiptStatus `json:"status"`
+}
+
+// Item defines model for Item.
+type Item struct {
+	// Price The total price payed for this item.
+	Price string `json:"price"`
+
+	// ShortDescription The Short Product Description for the item.
+	ShortDescription string `json:"shortDescription"`
+}
+
+// LedgerEntry defines model for LedgerEntry.
+type LedgerEntry struct {
+	// Balance The user's balance once the entry was posted.
+	Balance   int64     `json:"balance"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// ExpiresAt When any unspent part of a credit expires, absent if it never does.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	ID        string     `json:"id"`
+
+	// Points The change to the user's balance.
+	Points int64   `json:"points"`
+	Reason *string `json:"reason,omitempty"`
+
+	// ReceiptID The receipt a credit or its adjustment is for.
+	ReceiptID *string         `json:"receiptId,omitempty"`
+	Type      LedgerEntryType `json:"type"`
+}
+
+// LedgerEntryType defines model for LedgerEntry.Type.
+type LedgerEntryType string
+
+// LedgerResponse defines model for LedgerResponse.
+type LedgerResponse struct {
+	Entries []LedgerEntry `json:"entries"`
+}
+
+// OriginalPurchase The purchase date and time as they were written on the submitted receipt, before being
+// normalized to YYYY-MM-DD and HH:MM for scoring.
+type OriginalPurchase struct {
+	PurchaseDate string `json:"purchaseDate"`
+	PurchaseTime string `json:"purchaseTime"`
+}
+
+// PendingReceipt defines model for PendingReceipt.
+type PendingReceipt struct {
+	// Flags Why the fraud checks flagged the receipt.
+	Flags []string `json:"flags"`
+	ID    string   `json:"id"`
+
+	// Points The points that will be credited if the receipt is approved.
+	Points       int64     `json:"points"`
+	PurchaseDate string    `json:"purchaseDate"`
+	Retailer     string    `json:"retailer"`
+	SubmittedAt  time.Time `json:"submittedAt"`
+	Total        string    `json:"total"`
+	UserID       *string   `json:"userId,omitempty"`
+}
+
+// PendingReceiptsResponse defines model for PendingReceiptsResponse.
+type PendingReceiptsResponse struct {
+	Receipts []PendingReceipt `json:"receipts"`
+}
+
+// PointsBreakdown How the receipt's points were calculated. Returned by /receipts/explain, and by /v2 alongside the
+// points of a receipt once it is scored.
+type PointsBreakdown struct {
+	Points int64        `json:"points"`
+	Rules  []PointsRule `json:"rules"`
+}
+
+// PointsRule The points a scoring rule awarded, or an earning cap withheld
+type PointsRule struct {
+	Description string `json:"description"`
+	Points      int64  `json:"points"`
+}
+
+// PostReceiptResponse defines model for PostReceiptResponse.
+type PostReceiptResponse struct {
+	ID string `json:"id"`
+}
+
+// Receipt defines model for Receipt.
+type Receipt struct {
+	Items []Item `json:"items"`
+
+	// PurchaseDate The date of the purchase printed on the receipt. Accepted as YYYY-MM-DD or MM/DD/YYYY by default, and stored as YYYY-MM-DD.
+	PurchaseDate string `json:"purchaseDate"`
+
+	// PurchaseTime The time of the purchase printed on the receipt. Accepted as 24-hour HH:MM or HH:MM:SS, or 12-hour time such as 2:33 PM, by default, and stored as HH:MM.
+	PurchaseTime string `json:"purchaseTime"`
+
+	// Retailer The name of the retailer or store the receipt is from.
+	Retailer string `json:"retailer"`
+
+	// Total The total amount paid on the receipt.
+	Total string `json:"total"`
+}
+
+// ReceiptCorrection defines model for ReceiptCorrection.
+type ReceiptCorrection struct {
+	ID string `json:"id"`
+
+	// Points The points awarded for the corrected receipt.
+	Points         int64 `json:"points"`
+	PreviousPoints int64 `json:"previousPoints"`
+
+	// Version Starts at 1 and increases with every correction.
+	Version int `json:"version"`
+}
+
+// ReceiptListResponse defines model for ReceiptListResponse.
+type ReceiptListResponse struct {
+	// NextCursor Pass as cursor to fetch the next page, absent on the last page.
+	NextCursor *string          `json:"nextCursor,omitempty"`
+	Receipts   []ReceiptSummary `json:"receipts"`
+}
+
+// ReceiptReview defines model for ReceiptReview.
+type ReceiptReview struct {
+	ID string `json:"id"`
+
+	// Points The points credited to the owner, zero when rejected.
+	Points     int64     `json:"points"`
+	Reason     *string   `json:"reason,omitempty"`
+	ReviewedAt time.Time `json:"reviewedAt"`
+
+	// Status Receipts are processing while queued for scoring in asynchronous mode, and failed if scoring
+	// them went wrong. Receipts flagged by the fraud checks stay pending until an admin approves or
+	// rejects them.
+	Status ReceiptStatus `json:"status"`
+}
+
+// ReceiptRevision defines model for ReceiptRevision.
+type ReceiptRevision struct {
+	// Action Whether the version was corrected or deleted.
+	Action AuditAction `json:"action"`
+
+	// Original The purchase date and time as they were written on the submitted receipt, before being
+	// normalized to YYYY-MM-DD and HH:MM for scoring.
+	Original *OriginalPurchase `json:"original,omitempty"`
+
+	// Points The points the version was credited.
+	Points    int64     `json:"points"`
+	Reason    *string   `json:"reason,omitempty"`
+	Receipt   Receipt   `json:"receipt"`
+	RevisedAt time.Time `json:"revisedAt"`
+
+	// Status Receipts are processing while queued for scoring in asynchronous mode, and failed if scoring
+	// them went wrong. Receipts flagged by the fraud checks stay pending until an admin approves or
+	// rejects them.
+	Status ReceiptStatus `json:"status"`
+
+	// Version The version that was replaced or deleted.
+	Version int `json:"version"`
+}
+
+// ReceiptRevisionsResponse defines model for ReceiptRevisionsResponse.
+type ReceiptRevisionsResponse struct {
+	Revisions []ReceiptRevision `json:"revisions"`
+}
+
+// ReceiptStatus Receipts are processing while queued for scoring in asynchronous mode, and failed if scoring
+// them went wrong. Receipts flagged by the fraud checks stay pending until an admin approves or
+// rejects them.
+type ReceiptStatus string
+
+// ReceiptSummary defines model for ReceiptSummary.
+type ReceiptSummary struct {
+	ID string `json:"id"`
+
+	// Original The purchase date and time as they were written on the submitted receipt, before being
+	// normalized to YYYY-MM-DD and HH:MM for scoring.
+	Original     *OriginalPurchase `json:"original,omitempty"`
+	Points       int64             `json:"points"`
+	PurchaseDate string            `json:"purchaseDate"`
+	PurchaseTime string            `json:"purchaseTime"`
+	Retailer     string            `json:"retailer"`
+	Total        string            `json:"total"`
+}
+
+// Redemption defines model for Redemption.
+type Redemption struct {
+	// Balance The user's balance after the redemption.
+	Balance   int64     `json:"balance"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// ID The ID of the ledger entry recording the redemption.
+	ID     string `json:"id"`
+	Points int64  `json:"points"`
+	Reward string `json:"reward"`
+	UserID string `json:"userId"`
+}
+
+// RedemptionRequest defines model for RedemptionRequest.
+type RedemptionRequest struct {
+	// Points The number of points to spend.
+	Points int64 `json:"points"`
+
+	// Reward What the points are being spent on.
+	Reward string `json:"reward"`
+}
+
+// UserPointsResponse defines model for UserPointsResponse.
+type UserPointsResponse struct {
+	// Available Points earned from receipts, less redemptions, expired points and any adjustments.
+	Available int64 `json:"available"`
+
+	// Balance Same as available, kept for existing clients.
+	Balance int64 `json:"balance"`
+
+	// Expired Points that expired unspent.
+	Expired int64 `json:"expired"`
+
+	// PendingExpiry The part of available that expires within the next 30 days unless spent.
+	PendingExpiry int64  `json:"pendingExpiry"`
+	UserID        string `json:"userId"`
+}
+
+// UserReceiptsResponse defines model for UserReceiptsResponse.
+type UserReceiptsResponse struct {
+	Receipts []ReceiptSummary `json:"receipts"`
+	UserID   string           `json:"userId"`
+}
+
+// WebhookDeadLetter defines model for WebhookDeadLetter.
+type WebhookDeadLetter struct {
+	At       time.Time `json:"at"`
+	Attempts int       `json:"attempts"`
+
+	// Event The body POSTed to subscribers. Each request carries X-Webhook-Event, X-Webhook-Timestamp and
+	// X-Webhook-Signature, the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the subscription's
+	// secret and prefixed with "sha256=".
+	Event          WebhookEvent `json:"event"`
+	LastError      string       `json:"lastError"`
+	SubscriptionID string       `json:"subscriptionId"`
+}
+
+// WebhookDeadLettersResponse defines model for WebhookDeadLettersResponse.
+type WebhookDeadLettersResponse struct {
+	DeadLetters []WebhookDeadLetter `json:"deadLetters"`
+}
+
+// WebhookDeliveriesResponse defines model for WebhookDeliveriesResponse.
+type WebhookDeliveriesResponse struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+}
+
+// WebhookDelivery defines model for WebhookDelivery.
+type WebhookDelivery struct {
+	At time.Time `json:"at"`
+
+	// Attempt Starts at 1 and increases with every retry.
+	Attempt   int     `json:"attempt"`
+	Error     *string `json:"error,omitempty"`
+	EventID   string  `json:"eventId"`
+	EventType string  `json:"eventType"`
+
+	// StatusCode The subscriber's response status, absent when it could not be reached.
+	StatusCode     *int   `json:"statusCode,omitempty"`
+	SubscriptionID string `json:"subscriptionId"`
+	URL            string `json:"url"`
+}
+
+// WebhookEvent The body POSTed to subscribers. Each request carries X-Webhook-Event, X-Webhook-Timestamp and
+// X-Webhook-Signature, the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the subscription's
+// secret and prefixed with "sha256=".
+type WebhookEvent struct {
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Data A receipt for receipt events, a Redemption for points.redeemed.
+	Data map[string]interface{} `json:"data"`
+	ID   string                 `json:"id"`
+	Type WebhookEventType       `json:"type"`
+}
+
+// WebhookEventType defines model for WebhookEvent.Type.
+type WebhookEventType string
+
+// AuditActor defines model for AuditActor.
+type AuditActor = string
+
+// AuditReceiptID defines model for AuditReceiptID.
+type AuditReceiptID = string
+
+// AuditSince defines model for AuditSince.
+type AuditSince = time.Time
+
+// AuditUntil defines model for AuditUntil.
+type AuditUntil = time.Time
+
+// AuditUserID defines model for AuditUserID.
+type AuditUserID = string
+
+// ReceiptID defines model for ReceiptID.
+type ReceiptID = string
+
+// UserID defines model for UserID.
+type UserID = string
+
+// Forbidden defines model for Forbidden.
+type Forbidden = ErrorResponse
+
+// NotPending defines model for NotPending.
+type NotPending = ErrorResponse
+
+// ReceiptNotFound defines model for ReceiptNotFound.
+type ReceiptNotFound = ErrorResponse
+
+// TooManyRequests defines model for TooManyRequests.
+type TooManyRequests = ErrorResponse
+
+// Unauthorized defines model for Unauthorized.
+type Unauthorized = ErrorResponse
+
+// ListAuditEventsParams defines parameters for ListAuditEvents.
+type ListAuditEventsParams struct {
+	// Action Only events of this action
+	Action *AuditAction `form:"action,omitempty" json:"action,omitempty"`
+
+	// ReceiptID Only events for this receipt
+	ReceiptID *AuditReceiptID `form:"receiptId,omitempty" json:"receiptId,omitempty"`
+
+	// UserID Only events affecting this user's points
+	UserID *AuditUserID `form:"userId,omitempty" json:"userId,omitempty"`
+
+	// Actor Only events performed by this caller
+	Actor *AuditActor `form:"actor,omitempty" json:"actor,omitempty"`
+
+	// Since Only events recorded at or after this time
+	Since *AuditSince `form:"since,omitempty" json:"since,omitempty"`
+
+	// Until Only events recorded before this time
+	Until *AuditUntil `form:"until,omitempty" json:"until,omitempty"`
+}
+
+// ExportAuditEventsParams defines parameters for ExportAuditEvents.
+type ExportAuditEventsParams struct {
+	// Action Only events of this action
+	Action *AuditAction `form:"action,omitempty" json:"action,omitempty"`
+
+	// ReceiptID Only events for this receipt
+	ReceiptID *AuditReceiptID `form:"receiptId,omitempty" json:"receiptId,omitempty"`
+
+	// UserID Only events affecting this user's points
+	UserID *AuditUserID `form:"userId,omitempty" json:"userId,omitempty"`
+
+	// Actor Only events performed by this caller
+	Actor *AuditActor `form:"actor,omitempty" json:"actor,omitempty"`
+
+	// Since Only events recorded at or after this time
+	Since *AuditSince `form:"since,omitempty" json:"since,omitempty"`
+
+	// Until Only events recorded before this time
+	Until *AuditUntil `form:"until,omitempty" json:"until,omitempty"`
+}
+
+// ListReceiptsParams defines parameters for ListReceipts.
+type ListReceiptsParams struct {
+	// Retailer Only receipts from this retailer, ignoring case
+	Retailer *string `form:"retailer,omitempty" json:"retailer,omitempty"`
+
+	// PurchasedFrom Only receipts purchased on or after this date
+	PurchasedFrom *string `form:"purchasedFrom,omitempty" json:"purchasedFrom,omitempty"`
+
+	// PurchasedTo Only receipts purchased on or before this date
+	PurchasedTo *string `form:"purchasedTo,omitempty" json:"purchasedTo,omitempty"`
+	MinPoints   *int64  `form:"minPoints,omitempty" json:"minPoints,omitempty"`
+	MaxPoints   *int64  `form:"maxPoints,omitempty" json:"maxPoints,omitempty"`
+	MinTotal    *string `form:"minTotal,omitempty" json:"minTotal,omitempty"`
+	MaxTotal    *string `form:"maxTotal,omitempty" json:"maxTotal,omitempty"`
+
+	// Sort Sort field, prefixed with - for descending order
+	Sort  *ListReceiptsParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+	Limit *int                    `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor The nextCursor from the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// ListReceiptsParamsSort defines parameters for ListReceipts.
+type ListReceiptsParamsSort string
+
+// StreamReceiptsParams defines parameters for StreamReceipts.
+type StreamReceiptsParams struct {
+	LastEventID *string `json:"Last-Event-ID,omitempty"`
+}
+
+// DeleteReceiptParams defines parameters for DeleteReceipt.
+type DeleteReceiptParams struct {
+	// Reason Why support staff changed the receipt, kept in the audit trail
+	Reason *string `form:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// CorrectReceiptParams defines parameters for CorrectReceipt.
+type CorrectReceiptParams struct {
+	// Reason Why support staff changed the receipt, kept in the audit trail
+	Reason *string `form:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// ApproveReceiptParams defines parameters for ApproveReceipt.
+type ApproveReceiptParams struct {
+	// Reason Why the receipt was approved, kept in the audit trail
+	Reason *string `form:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// RejectReceiptParams defines parameters for RejectReceipt.
+type RejectReceiptParams struct {
+	// Reason Why the receipt was rejected
+	Reason string `form:"reason" json:"reason"`
+}
+
+// ListWebhookDeliveriesParams defines parameters for ListWebhookDeliveries.
+type ListWebhookDeliveriesParams struct {
+	// SubscriptionID Only attempts for this subscription
+	SubscriptionID *string `form:"subscriptionId,omitempty" json:"subscriptionId,omitempty"`
+}
+
+// ProcessReceiptsJSONRequestBody defines body for ProcessReceipts for application/json ContentType.
+type ProcessReceiptsJSONRequestBody = BatchReceiptsRequest
+
+// ExplainPointsJSONRequestBody defines body for ExplainPoints for application/json ContentType.
+type ExplainPointsJSONRequestBody = Receipt
+
+// ProcessReceiptJSONRequestBody defines body for ProcessReceipt for application/json ContentType.
+type ProcessReceiptJSONRequestBody = Receipt
+
+// CorrectReceiptJSONRequestBody defines body for CorrectReceipt for application/json ContentType.
+type CorrectReceiptJSONRequestBody = Receipt
+
+// AdjustPointsJSONRequestBody defines body for AdjustPoints for application/json ContentType.
+type AdjustPointsJSONRequestBody = AdjustmentRequest
+
+// RedeemPointsJSONRequestBody defines body for RedeemPoints for application/json ContentType.
+type RedeemPointsJSONRequestBody = RedemptionRequest
@@ -0,0 +1,24 @@
+package openapi
+
+import (
+	_ "embed"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Spec is the raw api.yml document describing the service
+//
+//go:embed api.yml
+var Spec []byte
+
+// Load parses and validates the embedded spec
+func Load() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(Spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.Validate(openapi3.NewLoader().Context); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
@@ -0,0 +1,157 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+)
+
+// Limit lets a client make Burst requests at once, refilled at RequestsPerMinute
+type Limit struct {
+	RequestsPerMinute float64 `json:"requestsPerMinute"`
+	Burst             int     `json:"burst"`
+}
+
+// Config holds the limit for each route, keyed by method and route template
+// such as "POST /receipts/process". Routes without an entry use Default, and
+// are unlimited when Default is nil.
+type Config struct {
+	Default *Limit           `json:"default"`
+	Routes  map[string]Limit `json:"routes"`
+}
+
+// LoadConfig reads a rate limits file of the form
+//
+//	{"default": {"requestsPerMinute": 120, "burst": 20},
+//	 "routes": {"POST /receipts/process": {"requestsPerMinute": 10, "burst": 5}}}
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("reading rate limits file: %w", err)
+	}
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return Config{}, fmt.Errorf("parsing rate limits file: %w", err)
+	}
+	if config.Default != nil {
+		if err := config.Default.validate(); err != nil {
+			return Config{}, fmt.Errorf("default rate limit: %w", err)
+		}
+	}
+	for route, limit := range config.Routes {
+		if err := limit.validate(); err != nil {
+			return Config{}, fmt.Errorf("rate limit for %q: %w", route, err)
+		}
+	}
+	return config, nil
+}
+
+func (l Limit) validate() error {
+	if l.RequestsPerMinute <= 0 {
+		return fmt.Errorf("requestsPerMinute must be positive, got %v", l.RequestsPerMinute)
+	}
+	if l.Burst < 1 {
+		return fmt.Errorf("burst must be at least 1, got %d", l.Burst)
+	}
+	return nil
+}
+
+// Result describes a client's bucket once a request has been counted against it
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed, zero if it already is
+	RetryAfter time.Duration
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket will have refilled completely
+	full time.Time
+}
+
+// Limiter keeps a token bucket per route and client and is safe for concurrent use
+type Limiter struct {
+	mu        sync.Mutex
+	config    Config
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func New(config Config) *Limiter {
+	return &Limiter{
+		config:  config,
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow takes a token from the client's bucket for route. The second result is
+// false when the route has no limit.
+func (l *Limiter) Allow(route, client string) (Result, bool) {
+	limit, ok := l.config.Routes[route]
+	if !ok {
+		if l.config.Default == nil {
+			return Result{}, false
+		}
+		limit = *l.config.Default
+	}
+	perSecond := limit.RequestsPerMinute / 60
+	burst := float64(limit.Burst)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	key := route + " " + client
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, updated: now}
+		l.buckets[key] = b
+	}
+
+	// Refill for the time since the bucket was last used
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.updated).Seconds()*perSecond)
+	b.updated = now
+
+	result := Result{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / perSecond)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = seconds((burst - b.tokens) / perSecond)
+	b.full = now.Add(result.Reset)
+	return result, true
+}
+
+// sweep drops buckets that have refilled completely, since a new bucket starts
+// full anyway. It must be called with l.mu held.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if !now.Before(b.full) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
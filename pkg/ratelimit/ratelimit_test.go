@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := New(Config{Routes: map[string]Limit{
+		"POST /receipts/process": {RequestsPerMinute: 6, Burst: 2},
+	}})
+	limiter.now = func() time.Time { return now }
+
+	// Routes without a limit are never counted
+	if _, limited := limiter.Allow("GET /receipts", "ip:10.0.0.1"); limited {
+		t.Error("Expected a route without a limit to be unlimited")
+	}
+
+	// Define slice of test cases, each a request some time after the previous one
+	testCases := []struct {
+		description        string
+		client             string
+		after              time.Duration
+		expectedAllowed    bool
+		expectedRemaining  int
+		expectedRetryAfter time.Duration
+	}{
+		{description: "First request", client: "key:a", expectedAllowed: true, expectedRemaining: 1},
+		{description: "Burst", client: "key:a", expectedAllowed: true, expectedRemaining: 0},
+		{description: "Over the limit", client: "key:a", expectedAllowed: false, expectedRemaining: 0, expectedRetryAfter: 10 * time.Second},
+		{description: "Other clients have their own bucket", client: "key:b", expectedAllowed: true, expectedRemaining: 1},
+		{description: "Still limited", client: "key:a", after: 5 * time.Second, expectedAllowed: false, expectedRemaining: 0, expectedRetryAfter: 5 * time.Second},
+		{description: "Refilled", client: "key:a", after: 5 * time.Second, expectedAllowed: true, expectedRemaining: 0},
+	}
+
+	for _, testCase := range testCases {
+		now = now.Add(testCase.after)
+		result, limited := limiter.Allow("POST /receipts/process", testCase.client)
+		if !limited {
+			t.Fatalf("%s: expected the route to be limited", testCase.description)
+		}
+		if result.Allowed != testCase.expectedAllowed || result.Remaining != testCase.expectedRemaining || result.RetryAfter != testCase.expectedRetryAfter {
+			t.Errorf("%s: unexpected result %+v", testCase.description, result)
+		}
+		if result.Limit != 2 {
+			t.Errorf("%s: expected limit 2, got %d", testCase.description, result.Limit)
+		}
+	}
+
+	// Idle buckets that have refilled are dropped
+	now = now.Add(time.Hour)
+	limiter.Allow("POST /receipts/process", "key:c")
+	if len(limiter.buckets) != 1 {
+		t.Errorf("Expected only the new bucket to remain, got %d", len(limiter.buckets))
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	// Define slice of test cases
+	testCases := []struct {
+		description string
+		contents    string
+		expectError bool
+	}{
+		{
+			description: "Valid file",
+			contents:    `{"default": {"requestsPerMinute": 120, "burst": 20}, "routes": {"POST /receipts/process": {"requestsPerMinute": 10, "burst": 5}}}`,
+		},
+		{
+			description: "Zero rate",
+			contents:    `{"routes": {"POST /receipts/process": {"requestsPerMinute": 0, "burst": 5}}}`,
+			expectError: true,
+		},
+		{
+			description: "Zero burst",
+			contents:    `{"default": {"requestsPerMinute": 10}}`,
+			expectError: true,
+		},
+		{
+			description: "Malformed JSON",
+			contents:    `{"routes": [`,
+			expectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "limits.json")
+			if err := os.WriteFile(path, []byte(testCase.contents), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadConfig(path)
+			if (err != nil) != testCase.expectError {
+				t.Errorf("Expected error %v, got %v", testCase.expectError, err)
+			}
+		})
+	}
+}
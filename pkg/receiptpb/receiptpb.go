@@ -0,0 +1,4 @@
+// Package receiptpb holds the protobuf messages and gRPC service generated from receipts.proto
+package receiptpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative receipts.proto
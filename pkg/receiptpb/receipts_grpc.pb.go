@@ -0,0 +1,228 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.25.2
+// source: receipts.proto
+
+package receiptpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ReceiptProcessor_ProcessReceipt_FullMethodName  = "/receipts.v1.ReceiptProcessor/ProcessReceipt"
+	ReceiptProcessor_ProcessReceipts_FullMethodName = "/receipts.v1.ReceiptProcessor/ProcessReceipts"
+	ReceiptProcessor_GetPoints_FullMethodName       = "/receipts.v1.ReceiptProcessor/GetPoints"
+	ReceiptProcessor_ExplainPoints_FullMethodName   = "/receipts.v1.ReceiptProcessor/ExplainPoints"
+)
+
+// ReceiptProcessorClient is the client API for ReceiptProcessor service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ReceiptProcessorClient interface {
+	// ProcessReceipt validates, scores and stores a receipt.
+	ProcessReceipt(ctx context.Context, in *ProcessReceiptRequest, opts ...grpc.CallOption) (*ProcessReceiptResponse, error)
+	// ProcessReceipts processes several receipts, reporting each one's result separately.
+	ProcessReceipts(ctx context.Context, in *ProcessReceiptsRequest, opts ...grpc.CallOption) (*ProcessReceiptsResponse, error)
+	// GetPoints returns the points awarded to a stored receipt.
+	GetPoints(ctx context.Context, in *GetPointsRequest, opts ...grpc.CallOption) (*GetPointsResponse, error)
+	// ExplainPoints scores a receipt without storing it and breaks the points down by rule.
+	ExplainPoints(ctx context.Context, in *ExplainPointsRequest, opts ...grpc.CallOption) (*ExplainPointsResponse, error)
+}
+
+type receiptProcessorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReceiptProcessorClient(cc grpc.ClientConnInterface) ReceiptProcessorClient {
+	return &receiptProcessorClient{cc}
+}
+
+func (c *receiptProcessorClient) ProcessReceipt(ctx context.Context, in *ProcessReceiptRequest, opts ...grpc.CallOption) (*ProcessReceiptResponse, error) {
+	out := new(ProcessReceiptResponse)
+	err := c.cc.Invoke(ctx, ReceiptProcessor_ProcessReceipt_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *receiptProcessorClient) ProcessReceipts(ctx context.Context, in *ProcessReceiptsRequest, opts ...grpc.CallOption) (*ProcessReceiptsResponse, error) {
+	out := new(ProcessReceiptsResponse)
+	err := c.cc.Invoke(ctx, ReceiptProcessor_ProcessReceipts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *receiptProcessorClient) GetPoints(ctx context.Context, in *GetPointsRequest, opts ...grpc.CallOption) (*GetPointsResponse, error) {
+	out := new(GetPointsResponse)
+	err := c.cc.Invoke(ctx, ReceiptProcessor_GetPoints_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *receiptProcessorClient) ExplainPoints(ctx context.Context, in *ExplainPointsRequest, opts ...grpc.CallOption) (*ExplainPointsResponse, error) {
+	out := new(ExplainPointsResponse)
+	err := c.cc.Invoke(ctx, ReceiptProcessor_ExplainPoints_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReceiptProcessorServer is the server API for ReceiptProcessor service.
+// All implementations must embed UnimplementedReceiptProcessorServer
+// for forward compatibility
+type ReceiptProcessorServer interface {
+	// ProcessReceipt validates, scores and stores a receipt.
+	ProcessReceipt(context.Context, *ProcessReceiptRequest) (*ProcessReceiptResponse, error)
+	// ProcessReceipts processes several receipts, reporting each one's result separately.
+	ProcessReceipts(context.Context, *ProcessReceiptsRequest) (*ProcessReceiptsResponse, error)
+	// GetPoints returns the points awarded to a stored receipt.
+	GetPoints(context.Context, *GetPointsRequest) (*GetPointsResponse, error)
+	// ExplainPoints scores a receipt without storing it and breaks the points down by rule.
+	ExplainPoints(context.Context, *ExplainPointsRequest) (*ExplainPointsResponse, error)
+	mustEmbedUnimplementedReceiptProcessorServer()
+}
+
+// UnimplementedReceiptProcessorServer must be embedded to have forward compatible implementations.
+type UnimplementedReceiptProcessorServer struct {
+}
+
+func (UnimplementedReceiptProcessorServer) ProcessReceipt(context.Context, *ProcessReceiptRequest) (*ProcessReceiptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProcessReceipt not implemented")
+}
+func (UnimplementedReceiptProcessorServer) ProcessReceipts(context.Context, *ProcessReceiptsRequest) (*ProcessReceiptsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProcessReceipts not implemented")
+}
+func (UnimplementedReceiptProcessorServer) GetPoints(context.Context, *GetPointsRequest) (*GetPointsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPoints not implemented")
+}
+func (UnimplementedReceiptProcessorServer) ExplainPoints(context.Context, *ExplainPointsRequest) (*ExplainPointsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExplainPoints not implemented")
+}
+func (UnimplementedReceiptProcessorServer) mustEmbedUnimplementedReceiptProcessorServer() {}
+
+// UnsafeReceiptProcessorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReceiptProcessorServer will
+// result in compilation errors.
+type UnsafeReceiptProcessorServer interface {
+	mustEmbedUnimplementedReceiptProcessorServer()
+}
+
+func RegisterReceiptProcessorServer(s grpc.ServiceRegistrar, srv ReceiptProcessorServer) {
+	s.RegisterService(&ReceiptProcessor_ServiceDesc, srv)
+}
+
+func _ReceiptProcessor_ProcessReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProcessReceiptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReceiptProcessorServer).ProcessReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReceiptProcessor_ProcessReceipt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReceiptProcessorServer).ProcessReceipt(ctx, req.(*ProcessReceiptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReceiptProcessor_ProcessReceipts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProcessReceiptsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReceiptProcessorServer).ProcessReceipts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReceiptProcessor_ProcessReceipts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReceiptProcessorServer).ProcessReceipts(ctx, req.(*ProcessReceiptsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReceiptProcessor_GetPoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPointsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReceiptProcessorServer).GetPoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReceiptProcessor_GetPoints_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReceiptProcessorServer).GetPoints(ctx, req.(*GetPointsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReceiptProcessor_ExplainPoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExplainPointsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReceiptProcessorServer).ExplainPoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReceiptProcessor_ExplainPoints_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReceiptProcessorServer).ExplainPoints(ctx, req.(*ExplainPointsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ReceiptProcessor_ServiceDesc is the grpc.ServiceDesc for ReceiptProcessor service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ReceiptProcessor_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "receipts.v1.ReceiptProcessor",
+	HandlerType: (*ReceiptProcessorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ProcessReceipt",
+			Handler:    _ReceiptProcessor_ProcessReceipt_Handler,
+		},
+		{
+			MethodName: "ProcessReceipts",
+			Handler:    _ReceiptProcessor_ProcessReceipts_Handler,
+		},
+		{
+			MethodName: "GetPoints",
+			Handler:    _ReceiptProcessor_GetPoints_Handler,
+		},
+		{
+			MethodName: "ExplainPoints",
+			Handler:    _ReceiptProcessor_ExplainPoints_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "receipts.proto",
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+const (
+	AuditSubmitted AuditAction = "submitted"
+	// AuditRecalculated records a receipt rescored without changing its contents
+	AuditRecalculated AuditAction = "recalculated"
+	AuditCorrected    AuditAction = "corrected"
+	AuditDeleted      AuditAction = "deleted"
+	AuditRedeemed     AuditAction = "redeemed"
+	// AuditAdjusted records a manual change to a user's balance
+	AuditAdjusted AuditAction = "adjusted"
+	AuditApproved AuditAction = "approved"
+	AuditRejected AuditAction = "rejected"
+)
+
+// AuditEvent records one action that changed a receipt's points or a user's balance
+type AuditEvent struct {
+	ID     string
+	Action AuditAction
+	// Actor is the token subject or API key name that performed the action
+	Actor     string
+	RequestID string
+	ReceiptID string
+	UserID    string
+	// PointsBefore and PointsAfter are the receipt's points, or the user's balance for redemptions
+	PointsBefore int64
+	PointsAfter  int64
+	Reason       string
+	Timestamp    time.Time
+}
+
+// AuditFilter selects audit events; zero fields match every event
+type AuditFilter struct {
+	Action    AuditAction
+	ReceiptID string
+	UserID    string
+	Actor     string
+	Since     time.Time
+	Until     time.Time
+}
+
+func (f AuditFilter) matches(event AuditEvent) bool {
+	switch {
+	case f.Action != "" && event.Action != f.Action:
+		return false
+	case f.ReceiptID != "" && event.ReceiptID != f.ReceiptID:
+		return false
+	case f.UserID != "" && event.UserID != f.UserID:
+		return false
+	case f.Actor != "" && event.Actor != f.Actor:
+		return false
+	case !f.Since.IsZero() && event.Timestamp.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !event.Timestamp.Before(f.Until):
+		return false
+	}
+	return true
+}
+
+// RecordAudit appends the event to the audit log, assigning its ID and timestamp
+func (s *Store) RecordAudit(event AuditEvent) AuditEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event.ID = uuid.NewString()
+	event.Timestamp = s.now()
+	s.audit = append(s.audit, event)
+	return event
+}
+
+// AuditLog returns the events matching filter, oldest first
+func (s *Store) AuditLog(filter AuditFilter) []AuditEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var events []AuditEvent
+	for _, event := range s.audit {
+		if filter.matches(event) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
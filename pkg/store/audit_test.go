@@ -0,0 +1,50 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuditLog(t *testing.T) {
+	now := time.Date(2023, 6, 15, 12, 0, 0, 0, time.UTC)
+	s := New()
+	s.now = func() time.Time { return now }
+
+	s.RecordAudit(AuditEvent{Action: AuditSubmitted, Actor: "user-1", ReceiptID: "r1", UserID: "user-1", PointsAfter: 28})
+	now = now.Add(time.Hour)
+	s.RecordAudit(AuditEvent{Action: AuditCorrected, Actor: "support", ReceiptID: "r1", UserID: "user-1", PointsBefore: 28, PointsAfter: 109})
+	now = now.Add(time.Hour)
+	s.RecordAudit(AuditEvent{Action: AuditRedeemed, Actor: "user-1", UserID: "user-1", PointsBefore: 109, PointsAfter: 9})
+	s.RecordAudit(AuditEvent{Action: AuditSubmitted, Actor: "user-2", ReceiptID: "r2", UserID: "user-2", PointsAfter: 6})
+
+	// Define slice of test cases
+	testCases := []struct {
+		description string
+		filter      AuditFilter
+		expectedLen int
+	}{
+		{description: "No filter", filter: AuditFilter{}, expectedLen: 4},
+		{description: "By action", filter: AuditFilter{Action: AuditSubmitted}, expectedLen: 2},
+		{description: "By receipt", filter: AuditFilter{ReceiptID: "r1"}, expectedLen: 2},
+		{description: "By user and actor", filter: AuditFilter{UserID: "user-1", Actor: "user-1"}, expectedLen: 2},
+		{description: "Since is inclusive", filter: AuditFilter{Since: now.Add(-time.Hour)}, expectedLen: 3},
+		{description: "Until is exclusive", filter: AuditFilter{Until: now.Add(-time.Hour)}, expectedLen: 1},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			events := s.AuditLog(testCase.filter)
+			if len(events) != testCase.expectedLen {
+				t.Errorf("Expected %d events, got %d: %+v", testCase.expectedLen, len(events), events)
+			}
+		})
+	}
+
+	events := s.AuditLog(AuditFilter{})
+	if events[0].ID == "" || events[0].ID == events[1].ID {
+		t.Errorf("Expected unique event IDs, got %q and %q", events[0].ID, events[1].ID)
+	}
+	if !events[1].Timestamp.Equal(time.Date(2023, 6, 15, 13, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected timestamp %v", events[1].Timestamp)
+	}
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EarningCaps limits what a user can earn from receipts submitted on the same
+// UTC day. Zero fields are unlimited.
+type EarningCaps struct {
+	// DailyPoints caps the points a user earns per day
+	DailyPoints int64
+	// DailyRetailerReceipts is how many receipts from one retailer earn points per day
+	DailyRetailerReceipts int
+}
+
+// Cap is points withheld from a receipt by an earning cap
+type Cap struct {
+	Points int64
+	Reason string
+}
+
+// WithEarningCaps applies caps to every receipt saved for a user
+func WithEarningCaps(caps EarningCaps) Option {
+	return func(s *Store) {
+		s.caps = caps
+	}
+}
+
+// applyCaps returns the caps that limit receipt given what its owner's other receipts
+// earned that day. It must be called with s.mu held.
+func (s *Store) applyCaps(receipt Receipt) []Cap {
+	if s.caps == (EarningCaps{}) {
+		return nil
+	}
+
+	day := receipt.CreatedAt.UTC().Truncate(24 * time.Hour)
+	var earned int64
+	var retailerReceipts int
+	for _, id := range s.userReceipts[receipt.UserID] {
+		submitted := s.receipts[id]
+		if id == receipt.ID || submitted.Status != StatusApproved || !submitted.CreatedAt.UTC().Truncate(24*time.Hour).Equal(day) {
+			continue
+		}
+		earned += submitted.Points
+		if strings.EqualFold(submitted.Retailer, receipt.Retailer) {
+			retailerReceipts++
+		}
+	}
+
+	var caps []Cap
+	points := receipt.Points
+	if limit := s.caps.DailyRetailerReceipts; limit > 0 && retailerReceipts >= limit && points > 0 {
+		caps = append(caps, Cap{Points: points, Reason: fmt.Sprintf("daily limit of %d receipts from %s reached", limit, receipt.Retailer)})
+		points = 0
+	}
+	if limit := s.caps.DailyPoints; limit > 0 && earned+points > limit && points > 0 {
+		withheld := min(points, earned+points-limit)
+		caps = append(caps, Cap{Points: withheld, Reason: fmt.Sprintf("daily cap of %d points reached, %d already earned today", limit, earned)})
+	}
+	return caps
+}
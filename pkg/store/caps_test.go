@@ -0,0 +1,61 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"receipt-processor/pkg/models"
+)
+
+func TestEarningCaps(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	s := New(WithEarningCaps(EarningCaps{DailyPoints: 100, DailyRetailerReceipts: 2}))
+	s.now = func() time.Time { return now }
+
+	// Define slice of test cases, each submitted some time after the previous one
+	testCases := []struct {
+		description    string
+		userID         string
+		retailer       string
+		points         int64
+		after          time.Duration
+		expectedPoints int64
+		expectedCaps   int
+	}{
+		{description: "Under both caps", userID: "user-1", retailer: "Target", points: 60, expectedPoints: 60},
+		{description: "Reaches the daily points cap", userID: "user-1", retailer: "Walgreens", points: 60, expectedPoints: 40, expectedCaps: 1},
+		{description: "Nothing left today", userID: "user-1", retailer: "Walgreens", points: 10, expectedPoints: 0, expectedCaps: 1},
+		{description: "Other users are unaffected", userID: "user-2", retailer: "Target", points: 10, expectedPoints: 10},
+		{description: "Anonymous receipts are never capped", retailer: "Target", points: 500, expectedPoints: 500},
+		{description: "Caps reset the next day", userID: "user-1", retailer: "target", points: 10, after: 24 * time.Hour, expectedPoints: 10},
+		{description: "Second receipt from a retailer", userID: "user-1", retailer: "Target", points: 10, expectedPoints: 10},
+		{description: "Retailer receipt limit reached", userID: "user-1", retailer: "TARGET", points: 10, expectedPoints: 0, expectedCaps: 1},
+	}
+
+	for i, testCase := range testCases {
+		now = now.Add(testCase.after)
+		id := string(rune('a' + i))
+		caps := s.SaveReceipt(Receipt{ID: id, UserID: testCase.userID, Points: testCase.points, Receipt: models.Receipt{Retailer: testCase.retailer}})
+
+		receipt, _ := s.Receipt(id)
+		if receipt.Points != testCase.expectedPoints {
+			t.Errorf("%s: expected %d points, got %d", testCase.description, testCase.expectedPoints, receipt.Points)
+		}
+		if len(caps) != testCase.expectedCaps || len(receipt.Caps) != testCase.expectedCaps {
+			t.Errorf("%s: expected %d caps, got %+v recorded as %+v", testCase.description, testCase.expectedCaps, caps, receipt.Caps)
+		}
+
+		// The points withheld plus the points credited make up the score
+		withheld := int64(0)
+		for _, c := range caps {
+			withheld += c.Points
+		}
+		if receipt.Points+withheld != testCase.points {
+			t.Errorf("%s: %d credited and %d withheld do not add up to %d", testCase.description, receipt.Points, withheld, testCase.points)
+		}
+	}
+
+	if balance := s.Balance("user-1"); balance != 120 {
+		t.Errorf("Expected balance 120, got %d", balance)
+	}
+}
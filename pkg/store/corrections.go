@@ -0,0 +1,126 @@
+package store
+
+import (
+	"errors"
+	"time"
+
+	"receipt-processor/pkg/models"
+)
+
+var ErrReceiptNotFound = errors.New("receipt not found")
+
+type RevisionAction string
+
+const (
+	RevisionCorrected RevisionAction = "corrected"
+	RevisionDeleted   RevisionAction = "deleted"
+)
+
+// Revision is a version of a receipt as it was before a correction or deletion
+type Revision struct {
+	Receipt
+	Action    RevisionAction
+	Reason    string
+	RevisedAt time.Time
+}
+
+// CorrectReceipt replaces the receipt's contents and points, adjusting the owner's
+// balance by the difference once the receipt is approved. Approved receipts are capped
+// again against the rest of the day they were submitted. It returns the previous and
+// the corrected versions, and the caps that applied.
+func (s *Store) CorrectReceipt(id string, corrected models.Receipt, original Original, points int64, reason string) (Receipt, Receipt, []Cap, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, ok := s.receipts[id]
+	if !ok {
+		return Receipt{}, Receipt{}, nil, ErrReceiptNotFound
+	}
+	s.revise(previous, RevisionCorrected, reason)
+
+	current := previous
+	current.Receipt = corrected
+	current.Original = original
+	current.Points = points
+	current.Version++
+
+	// Pending receipts are capped when they are approved
+	current.Caps = nil
+	var caps []Cap
+	if current.UserID != "" && current.Status == StatusApproved {
+		caps = s.applyCaps(current)
+		for _, withheld := range caps {
+			current.Points -= withheld.Points
+		}
+		current.Caps = caps
+	}
+	s.receipts[id] = current
+
+	// Pending receipts have not been credited yet, so there is nothing to adjust
+	if delta := current.Points - previous.Points; current.UserID != "" && current.Status == StatusApproved && delta != 0 {
+		s.post(LedgerEntry{
+			UserID:    current.UserID,
+			Type:      EntryAdjustment,
+			Points:    delta,
+			ReceiptID: id,
+			Reason:    reason,
+			ExpiresAt: s.expiryFor(current),
+		}, AccountAdjustments)
+	}
+	return previous, current, caps, nil
+}
+
+// DeleteReceipt removes the receipt and reverses the points it credited to its owner
+func (s *Store) DeleteReceipt(id string, reason string) (Receipt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted, ok := s.receipts[id]
+	if !ok {
+		return Receipt{}, ErrReceiptNotFound
+	}
+	s.revise(deleted, RevisionDeleted, reason)
+	delete(s.receipts, id)
+
+	if deleted.UserID == "" {
+		return deleted, nil
+	}
+
+	ids := s.userReceipts[deleted.UserID]
+	for i, receiptID := range ids {
+		if receiptID == id {
+			s.userReceipts[deleted.UserID] = append(ids[:i:i], ids[i+1:]...)
+			break
+		}
+	}
+
+	// The balance may go negative if the points were already spent
+	if deleted.Status == StatusApproved && deleted.Points != 0 {
+		s.post(LedgerEntry{
+			UserID:    deleted.UserID,
+			Type:      EntryAdjustment,
+			Points:    -deleted.Points,
+			ReceiptID: id,
+			Reason:    reason,
+		}, AccountAdjustments)
+	}
+	return deleted, nil
+}
+
+// Revisions returns the receipt's superseded versions, oldest first
+func (s *Store) Revisions(id string) []Revision {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]Revision(nil), s.revisions[id]...)
+}
+
+// revise must be called with s.mu held
+func (s *Store) revise(receipt Receipt, action RevisionAction, reason string) {
+	s.revisions[receipt.ID] = append(s.revisions[receipt.ID], Revision{
+		Receipt:   receipt,
+		Action:    action,
+		Reason:    reason,
+		RevisedAt: s.now(),
+	})
+}
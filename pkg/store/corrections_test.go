@@ -0,0 +1,101 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"receipt-processor/pkg/models"
+)
+
+func TestCorrectReceipt(t *testing.T) {
+	s := New()
+	s.SaveReceipt(Receipt{ID: "r1", UserID: "user-1", Points: 28, Receipt: models.Receipt{Retailer: "Targt", Total: "35.35"}})
+
+	previous, current, _, err := s.CorrectReceipt("r1", models.Receipt{Retailer: "Target", Total: "35.00"}, Original{PurchaseDate: "03/20/2022"}, 103, "OCR misread total")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if previous.Points != 28 || previous.Retailer != "Targt" || previous.Version != 1 {
+		t.Errorf("Unexpected previous version %+v", previous)
+	}
+	if current.Points != 103 || current.Retailer != "Target" || current.Version != 2 || current.UserID != "user-1" || current.Original.PurchaseDate != "03/20/2022" {
+		t.Errorf("Unexpected corrected version %+v", current)
+	}
+
+	// The owner is credited the difference
+	if balance := s.Balance("user-1"); balance != 103 {
+		t.Errorf("Expected balance 103, got %d", balance)
+	}
+	entries := s.LedgerEntries("user-1")
+	if last := entries[len(entries)-1]; last.Type != EntryAdjustment || last.Points != 75 || last.ReceiptID != "r1" || last.Reason != "OCR misread total" {
+		t.Errorf("Unexpected adjustment entry %+v", last)
+	}
+
+	// The previous version is kept in the audit trail
+	revisions := s.Revisions("r1")
+	if len(revisions) != 1 || revisions[0].Action != RevisionCorrected || revisions[0].Retailer != "Targt" {
+		t.Errorf("Unexpected revisions %+v", revisions)
+	}
+
+	if _, _, _, err := s.CorrectReceipt("missing", models.Receipt{}, Original{}, 0, ""); !errors.Is(err, ErrReceiptNotFound) {
+		t.Errorf("Expected ErrReceiptNotFound, got %v", err)
+	}
+}
+
+func TestCorrectCappedReceipt(t *testing.T) {
+	s := New(WithEarningCaps(EarningCaps{DailyPoints: 100}))
+	s.SaveReceipt(Receipt{ID: "r1", UserID: "user-1", Points: 100, Receipt: models.Receipt{Retailer: "Target"}})
+	s.SaveReceipt(Receipt{ID: "r2", UserID: "user-1", Points: 500, Receipt: models.Receipt{Retailer: "Target"}})
+
+	// Resubmitting a capped receipt unchanged credits nothing more
+	_, current, caps, err := s.CorrectReceipt("r2", models.Receipt{Retailer: "Target"}, Original{}, 500, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Points != 0 || len(caps) != 1 || caps[0].Points != 500 {
+		t.Errorf("Expected the correction to be capped to 0 points, got %d with caps %+v", current.Points, caps)
+	}
+	if balance := s.Balance("user-1"); balance != 100 {
+		t.Errorf("Expected balance 100, got %d", balance)
+	}
+
+	// The corrected receipt's own points don't count against its cap
+	_, current, caps, _ = s.CorrectReceipt("r1", models.Receipt{Retailer: "Target"}, Original{}, 80, "")
+	if current.Points != 80 || len(caps) != 0 {
+		t.Errorf("Expected 80 uncapped points, got %d with caps %+v", current.Points, caps)
+	}
+	if balance := s.Balance("user-1"); balance != 80 {
+		t.Errorf("Expected balance 80, got %d", balance)
+	}
+}
+
+func TestDeleteReceipt(t *testing.T) {
+	s := New()
+	s.SaveReceipt(Receipt{ID: "r1", UserID: "user-1", Points: 28})
+	s.SaveReceipt(Receipt{ID: "r2", UserID: "user-1", Points: 109})
+	if _, err := s.Redeem("user-1", 120, "gift card"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.DeleteReceipt("r2", "fraudulent"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The points are reversed even though most were already spent
+	if balance := s.Balance("user-1"); balance != -92 {
+		t.Errorf("Expected balance -92, got %d", balance)
+	}
+	if _, ok := s.Receipt("r2"); ok {
+		t.Error("Expected deleted receipt to be gone")
+	}
+	if receipts := s.UserReceipts("user-1"); len(receipts) != 1 || receipts[0].ID != "r1" {
+		t.Errorf("Expected only r1 in the user's history, got %+v", receipts)
+	}
+	if revisions := s.Revisions("r2"); len(revisions) != 1 || revisions[0].Action != RevisionDeleted || revisions[0].Reason != "fraudulent" {
+		t.Errorf("Unexpected revisions %+v", revisions)
+	}
+
+	if _, err := s.DeleteReceipt("r2", ""); !errors.Is(err, ErrReceiptNotFound) {
+		t.Errorf("Expected ErrReceiptNotFound deleting twice, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+)
+
+// lot is the unspent remainder of a single credit
+type lot struct {
+	entryID   string
+	expiresAt time.Time
+	remaining int64
+}
+
+// PointTotals breaks a user's balance down by expiry
+type PointTotals struct {
+	// Available is the spendable balance, including points about to expire
+	Available int64
+	// PendingExpiry is the part of Available that expires within the requested window
+	PendingExpiry int64
+	// Expired is every point that has ever expired unspent
+	Expired int64
+}
+
+// PointTotals reports the user's balance along with points expiring within the window
+func (s *Store) PointTotals(userID string, window time.Duration) PointTotals {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	totals := PointTotals{Available: s.balances[UserAccount(userID)]}
+
+	cutoff := s.now().Add(window)
+	for _, l := range s.lots[userID] {
+		if !l.expiresAt.IsZero() && !l.expiresAt.After(cutoff) {
+			totals.PendingExpiry += l.remaining
+		}
+	}
+	for _, entry := range s.ledger {
+		if entry.UserID == userID && entry.Type == EntryExpiry {
+			totals.Expired -= entry.Points
+		}
+	}
+	return totals
+}
+
+// ExpirePoints debits every user's unspent points whose expiry has passed and
+// returns the expiry entries it posted
+func (s *Store) ExpirePoints() []LedgerEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var posted []LedgerEntry
+	for userID, lots := range s.lots {
+		expired := int64(0)
+		for _, l := range lots {
+			if !l.expiresAt.IsZero() && !l.expiresAt.After(now) {
+				expired += l.remaining
+			}
+		}
+		if expired == 0 {
+			continue
+		}
+
+		// Lots are spent soonest-expiring first, so this debit consumes exactly the expired lots
+		posted = append(posted, s.post(LedgerEntry{UserID: userID, Type: EntryExpiry, Points: -expired, Reason: "points expired"}, AccountExpired))
+	}
+	return posted
+}
+
+// RunExpiryJob calls ExpirePoints every interval until ctx is cancelled
+func (s *Store) RunExpiryJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, entry := range s.ExpirePoints() {
+			log.Printf("Expired %d points for user %s", -entry.Points, entry.UserID)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// expiryFor derives when the receipt's points expire from its purchase date
+func (s *Store) expiryFor(receipt Receipt) time.Time {
+	if s.expiryMonths == 0 {
+		return time.Time{}
+	}
+
+	purchased, err := time.Parse(time.DateOnly, receipt.PurchaseDate)
+	if err != nil {
+		purchased = receipt.CreatedAt
+	}
+	return purchased.AddDate(0, s.expiryMonths, 0)
+}
+
+// addLot must be called with s.mu held
+func (s *Store) addLot(entry LedgerEntry) {
+	lots := append(s.lots[entry.UserID], lot{entryID: entry.ID, expiresAt: entry.ExpiresAt, remaining: entry.Points})
+
+	// Keep lots ordered by expiry with never-expiring lots last
+	sort.SliceStable(lots, func(i, j int) bool {
+		if lots[i].expiresAt.IsZero() || lots[j].expiresAt.IsZero() {
+			return !lots[i].expiresAt.IsZero() && lots[j].expiresAt.IsZero()
+		}
+		return lots[i].expiresAt.Before(lots[j].expiresAt)
+	})
+	s.lots[entry.UserID] = lots
+}
+
+// spendLots must be called with s.mu held
+func (s *Store) spendLots(userID string, points int64) {
+	lots := s.lots[userID]
+	for len(lots) > 0 && points > 0 {
+		spent := min(lots[0].remaining, points)
+		lots[0].remaining -= spent
+		points -= spent
+		if lots[0].remaining == 0 {
+			lots = lots[1:]
+		}
+	}
+	s.lots[userID] = lots
+}
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"receipt-processor/pkg/models"
+)
+
+func TestExpirePoints(t *testing.T) {
+	now := time.Date(2023, 6, 15, 12, 0, 0, 0, time.UTC)
+	s := New(WithPointExpiry(12))
+	s.now = func() time.Time { return now }
+
+	// Expires 2023-06-01, 2023-07-01 and 2024-01-01 respectively
+	s.SaveReceipt(Receipt{ID: "r1", UserID: "user-1", Points: 50, Receipt: models.Receipt{PurchaseDate: "2022-06-01"}})
+	s.SaveReceipt(Receipt{ID: "r2", UserID: "user-1", Points: 30, Receipt: models.Receipt{PurchaseDate: "2022-07-01"}})
+	s.SaveReceipt(Receipt{ID: "r3", UserID: "user-1", Points: 20, Receipt: models.Receipt{PurchaseDate: "2023-01-01"}})
+
+	// Redemptions spend the soonest-expiring points first, leaving 10 of r1
+	if _, err := s.Redeem("user-1", 40, "gift card"); err != nil {
+		t.Fatal(err)
+	}
+
+	totals := s.PointTotals("user-1", 30*24*time.Hour)
+	if totals.Available != 60 || totals.PendingExpiry != 40 || totals.Expired != 0 {
+		t.Errorf("Unexpected totals before expiry %+v", totals)
+	}
+
+	// Only the remainder of r1 is past its expiry
+	posted := s.ExpirePoints()
+	if len(posted) != 1 || posted[0].Type != EntryExpiry || posted[0].Points != -10 {
+		t.Fatalf("Expected a single 10 point expiry entry, got %+v", posted)
+	}
+
+	totals = s.PointTotals("user-1", 30*24*time.Hour)
+	if totals.Available != 50 || totals.PendingExpiry != 30 || totals.Expired != 10 {
+		t.Errorf("Unexpected totals after expiry %+v", totals)
+	}
+
+	// Running the job again finds nothing new
+	if posted := s.ExpirePoints(); len(posted) != 0 {
+		t.Errorf("Expected no further expiry entries, got %+v", posted)
+	}
+
+	// A year later everything left has expired
+	now = now.AddDate(1, 0, 0)
+	s.ExpirePoints()
+	totals = s.PointTotals("user-1", 30*24*time.Hour)
+	if totals.Available != 0 || totals.PendingExpiry != 0 || totals.Expired != 60 {
+		t.Errorf("Unexpected totals a year later %+v", totals)
+	}
+	if balance := s.AccountBalance(AccountExpired); balance != 60 {
+		t.Errorf("Expected the expired account to balance the user debits, got %d", balance)
+	}
+}
+
+func TestPointsNeverExpireByDefault(t *testing.T) {
+	s := New()
+	s.SaveReceipt(Receipt{ID: "r1", UserID: "user-1", Points: 50, Receipt: models.Receipt{PurchaseDate: "2000-01-01"}})
+
+	if posted := s.ExpirePoints(); len(posted) != 0 {
+		t.Errorf("Expected no expiry entries, got %+v", posted)
+	}
+	if totals := s.PointTotals("user-1", time.Hour); totals.Available != 50 || totals.PendingExpiry != 0 {
+		t.Errorf("Unexpected totals %+v", totals)
+	}
+}
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EntryType string
+
+const (
+	// EntryCredit records points earned from a receipt
+	EntryCredit EntryType = "credit"
+	// EntryDebit records points spent on a redemption
+	EntryDebit EntryType = "debit"
+	// EntryAdjustment records a manual correction in either direction
+	EntryAdjustment EntryType = "adjustment"
+	// EntryExpiry records earned points that went unspent past their expiry
+	EntryExpiry EntryType = "expiry"
+)
+
+// System accounts that balance every posting to a user account, so the ledger as a whole always sums to zero
+const (
+	AccountIssued      = "system:issued"
+	AccountRedeemed    = "system:redeemed"
+	AccountAdjustments = "system:adjustments"
+	AccountExpired     = "system:expired"
+)
+
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
+// Posting moves points into (positive) or out of (negative) a single account
+type Posting struct {
+	Account string
+	Points  int64
+}
+
+// LedgerEntry is one balanced transaction on a user's account. Entries are only
+// ever appended; Points is the net change to the user's balance.
+type LedgerEntry struct {
+	ID        string
+	UserID    string
+	Type      EntryType
+	Points    int64
+	ReceiptID string
+	Reason    string
+	Postings  []Posting
+	// Balance is the user's balance once this entry was applied
+	Balance int64
+	// ExpiresAt is when any unspent part of a credit expires, zero if it never does
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+func UserAccount(userID string) string {
+	return "user:" + userID
+}
+
+// Balance returns the user's current point balance
+func (s *Store) Balance(userID string) int64 {
+	return s.AccountBalance(UserAccount(userID))
+}
+
+func (s *Store) AccountBalance(account string) int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.balances[account]
+}
+
+// LedgerEntries returns the user's ledger entries, oldest first
+func (s *Store) LedgerEntries(userID string) []LedgerEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var entries []LedgerEntry
+	for _, entry := range s.ledger {
+		if entry.UserID == userID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Redeem debits points from the user's balance, failing with ErrInsufficientBalance
+// rather than letting the balance go negative
+func (s *Store) Redeem(userID string, points int64, reason string) (LedgerEntry, error) {
+	if points <= 0 {
+		return LedgerEntry{}, fmt.Errorf("redemption must be for a positive number of points, got %d", points)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The balance check and the debit happen under the same lock so concurrent
+	// redemptions can't both spend the same points
+	if balance := s.balances[UserAccount(userID)]; balance < points {
+		return LedgerEntry{}, fmt.Errorf("%w: balance is %d, redemption needs %d", ErrInsufficientBalance, balance, points)
+	}
+
+	return s.post(LedgerEntry{UserID: userID, Type: EntryDebit, Points: -points, Reason: reason}, AccountRedeemed), nil
+}
+
+// Adjust corrects the user's balance by points, which may be negative
+func (s *Store) Adjust(userID string, points int64, reason string) LedgerEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.post(LedgerEntry{UserID: userID, Type: EntryAdjustment, Points: points, Reason: reason}, AccountAdjustments)
+}
+
+// post records entry against the user's account and the balancing system account.
+// It must be called with s.mu held.
+func (s *Store) post(entry LedgerEntry, systemAccount string) LedgerEntry {
+	entry.ID = uuid.NewString()
+	entry.CreatedAt = s.now()
+	entry.Postings = []Posting{
+		{Account: UserAccount(entry.UserID), Points: entry.Points},
+		{Account: systemAccount, Points: -entry.Points},
+	}
+
+	for _, posting := range entry.Postings {
+		s.balances[posting.Account] += posting.Points
+	}
+	entry.Balance = s.balances[UserAccount(entry.UserID)]
+
+	// Credits open a lot that can later expire, debits spend the soonest-expiring lots first
+	if entry.Points > 0 {
+		s.addLot(entry)
+	} else {
+		s.spendLots(entry.UserID, -entry.Points)
+	}
+
+	s.ledger = append(s.ledger, entry)
+	return entry
+}
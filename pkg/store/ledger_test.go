@@ -0,0 +1,109 @@
+package store
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestRedeem(t *testing.T) {
+	s := New()
+	s.SaveReceipt(Receipt{ID: "r1", UserID: "user-1", Points: 100})
+
+	testCases := []struct {
+		description     string
+		points          int64
+		expectedErr     error
+		expectedBalance int64
+	}{
+		{"Partial redemption", 60, nil, 40},
+		{"More than the balance", 41, ErrInsufficientBalance, 40},
+		{"Exactly the balance", 40, nil, 0},
+		{"Empty balance", 1, ErrInsufficientBalance, 0},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			entry, err := s.Redeem("user-1", testCase.points, "gift card")
+			if !errors.Is(err, testCase.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", testCase.expectedErr, err)
+			}
+			if err == nil && (entry.Type != EntryDebit || entry.Points != -testCase.points || entry.Balance != testCase.expectedBalance) {
+				t.Errorf("Unexpected entry %+v", entry)
+			}
+			if balance := s.Balance("user-1"); balance != testCase.expectedBalance {
+				t.Errorf("Expected balance %d, got %d", testCase.expectedBalance, balance)
+			}
+		})
+	}
+
+	if _, err := s.Redeem("user-1", 0, "nothing"); err == nil {
+		t.Error("Expected an error redeeming zero points")
+	}
+}
+
+func TestLedgerIsBalanced(t *testing.T) {
+	s := New()
+	s.SaveReceipt(Receipt{ID: "r1", UserID: "user-1", Points: 100})
+	s.SaveReceipt(Receipt{ID: "r2", UserID: "user-2", Points: 30})
+	s.Redeem("user-1", 25, "coffee")
+	s.Adjust("user-2", -10, "duplicate receipt")
+	s.Adjust("user-1", 5, "goodwill")
+
+	// Every entry's postings net to zero, so the accounts do too
+	total := int64(0)
+	for _, userID := range []string{"user-1", "user-2"} {
+		for _, entry := range s.LedgerEntries(userID) {
+			sum := int64(0)
+			for _, posting := range entry.Postings {
+				sum += posting.Points
+			}
+			if sum != 0 {
+				t.Errorf("Entry %s postings sum to %d, want 0", entry.ID, sum)
+			}
+		}
+		total += s.Balance(userID)
+	}
+	for _, account := range []string{AccountIssued, AccountRedeemed, AccountAdjustments} {
+		total += s.AccountBalance(account)
+	}
+	if total != 0 {
+		t.Errorf("Expected accounts to sum to 0, got %d", total)
+	}
+
+	if balance := s.Balance("user-1"); balance != 80 {
+		t.Errorf("Expected user-1 balance 80, got %d", balance)
+	}
+	if balance := s.Balance("user-2"); balance != 20 {
+		t.Errorf("Expected user-2 balance 20, got %d", balance)
+	}
+}
+
+func TestConcurrentRedemptionsNeverOverdraw(t *testing.T) {
+	s := New()
+	s.SaveReceipt(Receipt{ID: "r1", UserID: "user-1", Points: 100})
+
+	// 50 concurrent attempts to spend 10 points from a balance of 100
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.Redeem("user-1", 10, "sticker"); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != 10 {
+		t.Errorf("Expected exactly 10 redemptions to succeed, got %d", succeeded)
+	}
+	if balance := s.Balance("user-1"); balance != 0 {
+		t.Errorf("Expected balance 0, got %d", balance)
+	}
+}
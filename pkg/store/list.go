@@ -0,0 +1,185 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type SortField string
+
+const (
+	SortPurchaseDate SortField = "purchaseDate"
+	SortPoints       SortField = "points"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ReceiptFilter narrows a listing to receipts matching every field that is set
+type ReceiptFilter struct {
+	UserID   string
+	Retailer string
+	// PurchasedFrom and PurchasedTo are inclusive YYYY-MM-DD dates
+	PurchasedFrom string
+	PurchasedTo   string
+	MinPoints     *int64
+	MaxPoints     *int64
+	MinTotal      *float64
+	MaxTotal      *float64
+}
+
+type ListOptions struct {
+	Filter     ReceiptFilter
+	Sort       SortField
+	Descending bool
+	Limit      int
+	// Cursor is the next cursor returned with the previous page, empty for the first page
+	Cursor string
+}
+
+// cursor records where a page ended so the next one can resume after it
+type cursor struct {
+	Sort       SortField `json:"s"`
+	Descending bool      `json:"d"`
+	Purchased  string    `json:"p,omitempty"`
+	Points     int64     `json:"n,omitempty"`
+	ID         string    `json:"i"`
+}
+
+// ListReceipts returns one page of matching receipts and the cursor for the next
+// page, which is empty once there are no more
+func (s *Store) ListReceipts(options ListOptions) ([]Receipt, string, error) {
+	if options.Sort == "" {
+		options.Sort = SortPurchaseDate
+	}
+
+	var after *cursor
+	if options.Cursor != "" {
+		decoded, err := decodeCursor(options.Cursor)
+		if err != nil || decoded.Sort != options.Sort || decoded.Descending != options.Descending {
+			return nil, "", ErrInvalidCursor
+		}
+		after = &decoded
+	}
+
+	// Collect matching receipts in sort order
+	s.mu.RLock()
+	var matches []Receipt
+	for _, receipt := range s.receipts {
+		if options.Filter.matches(receipt) {
+			matches = append(matches, receipt)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		return compareReceipts(keyOf(matches[i], options), keyOf(matches[j], options)) < 0
+	})
+
+	// Skip everything up to and including the previous page's last receipt
+	start := 0
+	if after != nil {
+		start = sort.Search(len(matches), func(i int) bool {
+			return compareReceipts(keyOf(matches[i], options), *after) > 0
+		})
+	}
+
+	end := len(matches)
+	if options.Limit > 0 && start+options.Limit < end {
+		end = start + options.Limit
+	}
+	page := matches[start:end]
+
+	next := ""
+	if end < len(matches) {
+		next = encodeCursor(keyOf(page[len(page)-1], options))
+	}
+	return page, next, nil
+}
+
+func (f ReceiptFilter) matches(receipt Receipt) bool {
+	if f.UserID != "" && receipt.UserID != f.UserID {
+		return false
+	}
+	if f.Retailer != "" && !strings.EqualFold(receipt.Retailer, f.Retailer) {
+		return false
+	}
+	if f.PurchasedFrom != "" && receipt.PurchaseDate < f.PurchasedFrom {
+		return false
+	}
+	if f.PurchasedTo != "" && receipt.PurchaseDate > f.PurchasedTo {
+		return false
+	}
+	if f.MinPoints != nil && receipt.Points < *f.MinPoints {
+		return false
+	}
+	if f.MaxPoints != nil && receipt.Points > *f.MaxPoints {
+		return false
+	}
+	if f.MinTotal != nil || f.MaxTotal != nil {
+		total, err := strconv.ParseFloat(strings.ReplaceAll(receipt.Total, ",", ""), 64)
+		if err != nil {
+			return false
+		}
+		if f.MinTotal != nil && total < *f.MinTotal {
+			return false
+		}
+		if f.MaxTotal != nil && total > *f.MaxTotal {
+			return false
+		}
+	}
+	return true
+}
+
+func keyOf(receipt Receipt, options ListOptions) cursor {
+	key := cursor{Sort: options.Sort, Descending: options.Descending, ID: receipt.ID}
+	switch options.Sort {
+	case SortPoints:
+		key.Points = receipt.Points
+	default:
+		key.Purchased = receipt.PurchaseDate + " " + receipt.PurchaseTime
+	}
+	return key
+}
+
+// compareReceipts orders by the sort field, breaking ties by ID so pages never overlap
+func compareReceipts(a, b cursor) int {
+	result := 0
+	switch a.Sort {
+	case SortPoints:
+		if a.Points != b.Points {
+			result = 1
+			if a.Points < b.Points {
+				result = -1
+			}
+		}
+	default:
+		result = strings.Compare(a.Purchased, b.Purchased)
+	}
+	if result == 0 {
+		result = strings.Compare(a.ID, b.ID)
+	}
+
+	if a.Descending {
+		return -result
+	}
+	return result
+}
+
+func encodeCursor(key cursor) string {
+	data, _ := json.Marshal(key)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value string) (cursor, error) {
+	var key cursor
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return key, err
+	}
+	err = json.Unmarshal(data, &key)
+	return key, err
+}
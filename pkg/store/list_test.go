@@ -0,0 +1,108 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"receipt-processor/pkg/models"
+)
+
+func newListStore() *Store {
+	s := New()
+	s.SaveReceipt(Receipt{ID: "a", UserID: "user-1", Points: 28, Receipt: models.Receipt{Retailer: "Target", PurchaseDate: "2022-01-01", PurchaseTime: "13:01", Total: "35.35"}})
+	s.SaveReceipt(Receipt{ID: "b", UserID: "user-1", Points: 109, Receipt: models.Receipt{Retailer: "M&M Corner Market", PurchaseDate: "2022-03-20", PurchaseTime: "14:33", Total: "9.00"}})
+	s.SaveReceipt(Receipt{ID: "c", UserID: "user-2", Points: 31, Receipt: models.Receipt{Retailer: "Target", PurchaseDate: "2022-01-02", PurchaseTime: "13:13", Total: "1.25"}})
+	s.SaveReceipt(Receipt{ID: "d", UserID: "user-2", Points: 31, Receipt: models.Receipt{Retailer: "Walgreens", PurchaseDate: "2022-01-02", PurchaseTime: "08:13", Total: "2.65"}})
+	s.SaveReceipt(Receipt{ID: "e", Points: 6, Receipt: models.Receipt{Retailer: "target", PurchaseDate: "2022-02-14", PurchaseTime: "09:00", Total: "1,000.00"}})
+	return s
+}
+
+func TestListReceipts(t *testing.T) {
+	s := newListStore()
+
+	minPoints, maxPoints := int64(30), int64(100)
+	minTotal, maxTotal := 2.0, 40.0
+
+	testCases := []struct {
+		description string
+		options     ListOptions
+		expectedIDs []string
+	}{
+		{"All by purchase date", ListOptions{}, []string{"a", "d", "c", "e", "b"}},
+		{"All by purchase date descending", ListOptions{Descending: true}, []string{"b", "e", "c", "d", "a"}},
+		{"By points with ID tie-break", ListOptions{Sort: SortPoints}, []string{"e", "a", "c", "d", "b"}},
+		{"By points descending", ListOptions{Sort: SortPoints, Descending: true}, []string{"b", "d", "c", "a", "e"}},
+		{"Retailer ignores case", ListOptions{Filter: ReceiptFilter{Retailer: "TARGET"}}, []string{"a", "c", "e"}},
+		{"Purchase date range", ListOptions{Filter: ReceiptFilter{PurchasedFrom: "2022-01-02", PurchasedTo: "2022-02-14"}}, []string{"d", "c", "e"}},
+		{"Points range", ListOptions{Filter: ReceiptFilter{MinPoints: &minPoints, MaxPoints: &maxPoints}}, []string{"d", "c"}},
+		{"Total range", ListOptions{Filter: ReceiptFilter{MinTotal: &minTotal, MaxTotal: &maxTotal}}, []string{"a", "d", "b"}},
+		{"Owner", ListOptions{Filter: ReceiptFilter{UserID: "user-2"}}, []string{"d", "c"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			receipts, next, err := s.ListReceipts(testCase.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next != "" {
+				t.Errorf("Expected no next cursor without a limit, got %q", next)
+			}
+
+			ids := make([]string, len(receipts))
+			for i, receipt := range receipts {
+				ids[i] = receipt.ID
+			}
+			if len(ids) != len(testCase.expectedIDs) {
+				t.Fatalf("Expected %v, got %v", testCase.expectedIDs, ids)
+			}
+			for i := range ids {
+				if ids[i] != testCase.expectedIDs[i] {
+					t.Fatalf("Expected %v, got %v", testCase.expectedIDs, ids)
+				}
+			}
+		})
+	}
+}
+
+func TestListReceiptsPagination(t *testing.T) {
+	s := newListStore()
+
+	// Walk the listing two at a time
+	options := ListOptions{Sort: SortPoints, Descending: true, Limit: 2}
+	var ids []string
+	pages := 0
+	for {
+		receipts, next, err := s.ListReceipts(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages++
+		for _, receipt := range receipts {
+			ids = append(ids, receipt.ID)
+		}
+		if next == "" {
+			break
+		}
+		options.Cursor = next
+	}
+
+	expected := []string{"b", "d", "c", "a", "e"}
+	if pages != 3 || len(ids) != len(expected) {
+		t.Fatalf("Expected %v over 3 pages, got %v over %d", expected, ids, pages)
+	}
+	for i := range ids {
+		if ids[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, ids)
+		}
+	}
+
+	// A cursor only resumes the listing it came from
+	_, next, _ := s.ListReceipts(ListOptions{Sort: SortPoints, Limit: 1})
+	if _, _, err := s.ListReceipts(ListOptions{Sort: SortPurchaseDate, Limit: 1, Cursor: next}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor for a cursor from another sort, got %v", err)
+	}
+	if _, _, err := s.ListReceipts(ListOptions{Cursor: "not-a-cursor"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor for garbage, got %v", err)
+	}
+}
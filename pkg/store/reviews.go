@@ -0,0 +1,85 @@
+package store
+
+import (
+	"errors"
+	"sort"
+)
+
+var ErrNotPending = errors.New("receipt is not pending review")
+
+// PendingReceipts returns the receipts held for review, oldest first
+func (s *Store) PendingReceipts() []Receipt {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var pending []Receipt
+	for _, receipt := range s.receipts {
+		if receipt.Status == StatusPending {
+			pending = append(pending, receipt)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		if !pending[i].CreatedAt.Equal(pending[j].CreatedAt) {
+			return pending[i].CreatedAt.Before(pending[j].CreatedAt)
+		}
+		return pending[i].ID < pending[j].ID
+	})
+	return pending
+}
+
+// ApproveReceipt credits a pending receipt's points to its owner, subject to the
+// earning caps of the day it was submitted. It returns the caps that applied.
+func (s *Store) ApproveReceipt(id string, reason string) (Receipt, []Cap, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	receipt, ok := s.receipts[id]
+	if !ok {
+		return Receipt{}, nil, ErrReceiptNotFound
+	}
+	if receipt.Status != StatusPending {
+		return Receipt{}, nil, ErrNotPending
+	}
+	receipt.Status = StatusApproved
+	receipt.ReviewReason = reason
+	receipt.ReviewedAt = s.now()
+
+	if receipt.UserID == "" {
+		s.receipts[id] = receipt
+		return receipt, nil, nil
+	}
+	caps := s.applyCaps(receipt)
+	for _, withheld := range caps {
+		receipt.Points -= withheld.Points
+	}
+	receipt.Caps = caps
+	s.receipts[id] = receipt
+
+	s.post(LedgerEntry{
+		UserID:    receipt.UserID,
+		Type:      EntryCredit,
+		Points:    receipt.Points,
+		ReceiptID: receipt.ID,
+		ExpiresAt: s.expiryFor(receipt),
+	}, AccountIssued)
+	return receipt, caps, nil
+}
+
+// RejectReceipt closes a pending receipt's review without crediting its points
+func (s *Store) RejectReceipt(id string, reason string) (Receipt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	receipt, ok := s.receipts[id]
+	if !ok {
+		return Receipt{}, ErrReceiptNotFound
+	}
+	if receipt.Status != StatusPending {
+		return Receipt{}, ErrNotPending
+	}
+	receipt.Status = StatusRejected
+	receipt.ReviewReason = reason
+	receipt.ReviewedAt = s.now()
+	s.receipts[id] = receipt
+	return receipt, nil
+}
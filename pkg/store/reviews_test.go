@@ -0,0 +1,62 @@
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReviewPendingReceipts(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	s := New(WithEarningCaps(EarningCaps{DailyPoints: 100}))
+	s.now = func() time.Time { return now }
+
+	s.SaveReceipt(Receipt{ID: "r1", UserID: "user-1", Points: 80, Status: StatusPending})
+	now = now.Add(time.Minute)
+	s.SaveReceipt(Receipt{ID: "r2", UserID: "user-1", Points: 50, Status: StatusPending})
+	now = now.Add(time.Minute)
+	s.SaveReceipt(Receipt{ID: "r3", UserID: "user-1", Points: 40})
+
+	pending := s.PendingReceipts()
+	if len(pending) != 2 || pending[0].ID != "r1" || pending[1].ID != "r2" {
+		t.Fatalf("Expected r1 and r2 pending, got %+v", pending)
+	}
+
+	// Approving credits the points, capped by what was already earned that day
+	approved, caps, err := s.ApproveReceipt("r1", "confirmed with retailer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if approved.Status != StatusApproved || approved.Points != 60 || len(caps) != 1 || approved.ReviewReason != "confirmed with retailer" {
+		t.Errorf("Unexpected approval %+v with caps %+v", approved, caps)
+	}
+	if balance := s.Balance("user-1"); balance != 100 {
+		t.Errorf("Expected balance 100, got %d", balance)
+	}
+
+	// Rejecting leaves the balance alone
+	rejected, err := s.RejectReceipt("r2", "duplicate of r1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rejected.Status != StatusRejected || !rejected.ReviewedAt.Equal(now) {
+		t.Errorf("Unexpected rejection %+v", rejected)
+	}
+	if balance := s.Balance("user-1"); balance != 100 {
+		t.Errorf("Expected balance 100 after rejection, got %d", balance)
+	}
+	if pending := s.PendingReceipts(); len(pending) != 0 {
+		t.Errorf("Expected no pending receipts, got %+v", pending)
+	}
+
+	// Only pending receipts can be reviewed
+	if _, _, err := s.ApproveReceipt("r2", ""); !errors.Is(err, ErrNotPending) {
+		t.Errorf("Expected ErrNotPending approving a rejected receipt, got %v", err)
+	}
+	if _, err := s.RejectReceipt("r3", "late"); !errors.Is(err, ErrNotPending) {
+		t.Errorf("Expected ErrNotPending rejecting an approved receipt, got %v", err)
+	}
+	if _, err := s.RejectReceipt("missing", "late"); !errors.Is(err, ErrReceiptNotFound) {
+		t.Errorf("Expected ErrReceiptNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package store
+
+import (
+	"sync"
+	"time"
+
+	"receipt-processor/pkg/models"
+)
+
+type ReceiptStatus string
+
+const (
+	// StatusProcessing receipts are still queued for scoring and are never stored
+	StatusProcessing ReceiptStatus = "processing"
+	// StatusFailed receipts could not be scored after they were queued and are never stored
+	StatusFailed ReceiptStatus = "failed"
+	// StatusApproved receipts have had their points credited
+	StatusApproved ReceiptStatus = "approved"
+	// StatusPending receipts were flagged as suspicious and are held until reviewed
+	StatusPending ReceiptStatus = "pending"
+	// StatusRejected receipts failed review and were never credited
+	StatusRejected ReceiptStatus = "rejected"
+)
+
+// Receipt is a processed receipt along with who submitted it and what it scored
+type Receipt struct {
+	// Receipt has its purchase date and time normalized to YYYY-MM-DD and HH:MM
+	models.Receipt
+	// Original is how the purchase date and time were written on the submitted receipt
+	Original Original
+	ID       string
+	// UserID is the subject of the token the receipt was submitted with, empty when anonymous
+	UserID string
+	// Points is what the receipt scored, not yet credited while it is pending
+	Points int64
+	// Caps are what the earning caps withheld from Points when it was credited
+	Caps   []Cap
+	Status ReceiptStatus
+	// Flags describes why a pending receipt looked suspicious
+	Flags []string
+	// ReviewReason and ReviewedAt record how a pending receipt's review was closed
+	ReviewReason string
+	ReviewedAt   time.Time
+	// Version starts at 1 and increases with every correction
+	Version   int
+	CreatedAt time.Time
+}
+
+// Original holds a receipt's purchase date and time as submitted, before they were normalized
+type Original struct {
+	PurchaseDate string
+	PurchaseTime string
+}
+
+// Store keeps processed receipts and the points ledger in memory and is safe for concurrent use
+type Store struct {
+	mu       sync.RWMutex
+	receipts map[string]Receipt
+	// userReceipts holds each user's receipt IDs in submission order
+	userReceipts map[string][]string
+	ledger       []LedgerEntry
+	// balances is the running total of every account's postings
+	balances map[string]int64
+	// lots tracks the unspent part of each user's credits so they can expire
+	lots map[string][]lot
+	// revisions keeps every superseded or deleted version of a receipt
+	revisions map[string][]Revision
+	// audit is append-only, nothing removes or rewrites its events
+	audit []AuditEvent
+
+	expiryMonths int
+	caps         EarningCaps
+	now          func() time.Time
+}
+
+type Option func(*Store)
+
+// WithPointExpiry makes points earned from a receipt expire the given number of
+// months after its purchase date. Points never expire when months is zero.
+func WithPointExpiry(months int) Option {
+	return func(s *Store) {
+		s.expiryMonths = months
+	}
+}
+
+func New(options ...Option) *Store {
+	s := &Store{
+		receipts:     make(map[string]Receipt),
+		userReceipts: make(map[string][]string),
+		balances:     make(map[string]int64),
+		lots:         make(map[string][]lot),
+		revisions:    make(map[string][]Revision),
+		now:          time.Now,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// SaveReceipt stores the receipt and, when it belongs to a user and is not pending, credits
+// its points to them. It returns the earning caps that reduced the points credited, if any.
+func (s *Store) SaveReceipt(receipt Receipt) []Cap {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if receipt.CreatedAt.IsZero() {
+		receipt.CreatedAt = s.now()
+	}
+	if receipt.Status == "" {
+		receipt.Status = StatusApproved
+	}
+	receipt.Version = 1
+
+	if receipt.UserID == "" {
+		s.receipts[receipt.ID] = receipt
+		return nil
+	}
+	if receipt.Status == StatusPending {
+		s.receipts[receipt.ID] = receipt
+		s.userReceipts[receipt.UserID] = append(s.userReceipts[receipt.UserID], receipt.ID)
+		return nil
+	}
+	caps := s.applyCaps(receipt)
+	for _, withheld := range caps {
+		receipt.Points -= withheld.Points
+	}
+	receipt.Caps = caps
+	s.receipts[receipt.ID] = receipt
+
+	s.userReceipts[receipt.UserID] = append(s.userReceipts[receipt.UserID], receipt.ID)
+	s.post(LedgerEntry{
+		UserID:    receipt.UserID,
+		Type:      EntryCredit,
+		Points:    receipt.Points,
+		ReceiptID: receipt.ID,
+		ExpiresAt: s.expiryFor(receipt),
+	}, AccountIssued)
+	return caps
+}
+
+// SubmissionsSince counts the receipts the user submitted at or after since
+func (s *Store) SubmissionsSince(userID string, since time.Time) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+	for _, id := range s.userReceipts[userID] {
+		if !s.receipts[id].CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *Store) Receipt(id string) (Receipt, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	receipt, ok := s.receipts[id]
+	return receipt, ok
+}
+
+// UserReceipts returns the receipts submitted by the user, oldest first
+func (s *Store) UserReceipts(userID string) []Receipt {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := s.userReceipts[userID]
+	receipts := make([]Receipt, 0, len(ids))
+	for _, id := range ids {
+		receipts = append(receipts, s.receipts[id])
+	}
+	return receipts
+}
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"receipt-processor/pkg/models"
+)
+
+func TestSaveReceiptCreditsOwner(t *testing.T) {
+	s := New()
+
+	s.SaveReceipt(Receipt{ID: "r1", UserID: "user-1", Points: 28, Receipt: models.Receipt{Retailer: "Target"}})
+	s.SaveReceipt(Receipt{ID: "r2", UserID: "user-1", Points: 109, Receipt: models.Receipt{Retailer: "M&M Corner Market"}})
+	s.SaveReceipt(Receipt{ID: "r3", UserID: "user-2", Points: 5})
+	s.SaveReceipt(Receipt{ID: "r4", Points: 50})
+
+	testCases := []struct {
+		userID          string
+		expectedBalance int64
+		expectedIDs     []string
+	}{
+		{"user-1", 137, []string{"r1", "r2"}},
+		{"user-2", 5, []string{"r3"}},
+		{"user-3", 0, nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.userID, func(t *testing.T) {
+			if balance := s.Balance(testCase.userID); balance != testCase.expectedBalance {
+				t.Errorf("Expected balance %d, got %d", testCase.expectedBalance, balance)
+			}
+
+			receipts := s.UserReceipts(testCase.userID)
+			if len(receipts) != len(testCase.expectedIDs) {
+				t.Fatalf("Expected %d receipts, got %d", len(testCase.expectedIDs), len(receipts))
+			}
+			for i, receipt := range receipts {
+				if receipt.ID != testCase.expectedIDs[i] {
+					t.Errorf("Expected receipt %d to be %s, got %s", i, testCase.expectedIDs[i], receipt.ID)
+				}
+			}
+		})
+	}
+
+	// Anonymous receipts are stored but credit nobody
+	if _, ok := s.Receipt("r4"); !ok {
+		t.Error("Expected anonymous receipt to be stored")
+	}
+	if entries := s.LedgerEntries(""); len(entries) != 0 {
+		t.Errorf("Expected no ledger entries for anonymous receipts, got %d", len(entries))
+	}
+}
+
+func TestSavePendingReceipt(t *testing.T) {
+	s := New()
+	s.SaveReceipt(Receipt{ID: "r1", UserID: "user-1", Points: 28})
+	s.SaveReceipt(Receipt{ID: "r2", UserID: "user-1", Points: 109, Status: StatusPending, Flags: []string{"totalMismatch: total 9.00 does not match the items"}})
+
+	// Pending receipts are kept but not credited
+	if balance := s.Balance("user-1"); balance != 28 {
+		t.Errorf("Expected balance 28, got %d", balance)
+	}
+	if receipt, ok := s.Receipt("r2"); !ok || receipt.Status != StatusPending || receipt.Points != 109 {
+		t.Errorf("Unexpected pending receipt %+v", receipt)
+	}
+	if receipt, _ := s.Receipt("r1"); receipt.Status != StatusApproved {
+		t.Errorf("Expected receipts to default to %s, got %s", StatusApproved, receipt.Status)
+	}
+	if count := s.SubmissionsSince("user-1", time.Now().Add(-time.Hour)); count != 2 {
+		t.Errorf("Expected 2 recent submissions, got %d", count)
+	}
+
+	// Correcting or deleting a pending receipt leaves the balance alone
+	s.CorrectReceipt("r2", models.Receipt{Retailer: "Target"}, Original{}, 6, "")
+	s.DeleteReceipt("r2", "")
+	if balance := s.Balance("user-1"); balance != 28 {
+		t.Errorf("Expected balance 28 after removing the pending receipt, got %d", balance)
+	}
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"strconv"
@@ -9,52 +10,132 @@ import (
 	"unicode"
 
 	"receipt-processor/pkg/models"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func CalculatePoints(receipt models.Receipt) (int64, string) {
+var tracer = otel.Tracer("receipt-processor/pkg/utils")
+
+func CalculatePoints(receipt models.Receipt) (int64, []BreakdownLine) {
+	return CalculatePointsContext(context.Background(), receipt)
+}
+
+// BreakdownLine is a rule or cap listed in a breakdown
+type BreakdownLine struct {
+	Points      int64
+	Description string
+}
+
+// CalculatePointsContext scores the receipt, recording a span for each rule under ctx
+func CalculatePointsContext(ctx context.Context, receipt models.Receipt) (int64, []BreakdownLine) {
+	ctx, span := tracer.Start(ctx, "CalculatePoints", trace.WithAttributes(
+		attribute.Int("receipt.items", len(receipt.Items)),
+	))
+	defer span.End()
+
 	points := int64(0)
-	breakdown := ""
+	breakdown := []BreakdownLine{}
+
+	// Run a single rule inside its own span and add its result to the totals
+	score := func(name string, rule func() (int64, []BreakdownLine)) {
+		_, ruleSpan := tracer.Start(ctx, "rule."+name)
+		defer ruleSpan.End()
+
+		rulePoints, ruleBreakdown := rule()
+		ruleSpan.SetAttributes(attribute.Int64("receipt.rule.points", rulePoints))
+		points += rulePoints
+		breakdown = append(breakdown, ruleBreakdown...)
+	}
 
 	// Rule 1: One point for every alphanumeric character in the retailer name.
-	namePoints := countAlphaNumeric(receipt.Retailer)
-	points += namePoints
-	breakdown += fmt.Sprintf("%d points - retailer name (%s) has %d alphanumeric characters\n", namePoints, receipt.Retailer, namePoints)
+	score("retailerName", func() (int64, []BreakdownLine) {
+		namePoints := countAlphaNumeric(receipt.Retailer)
+		return namePoints, []BreakdownLine{{Points: namePoints, Description: fmt.Sprintf("retailer name (%s) has %d alphanumeric characters", receipt.Retailer, namePoints)}}
+	})
 	// Rule 2: 50 points if the total is a round dollar amount with no cents.
-	if isRoundDollarAmount(receipt.Total) {
-		points += 50
-		breakdown += "50 points - total is a round dollar amount\n"
-	}
+	score("roundDollarTotal", func() (int64, []BreakdownLine) {
+		if isRoundDollarAmount(receipt.Total) {
+			return 50, []BreakdownLine{{Points: 50, Description: "total is a round dollar amount"}}
+		}
+		return 0, nil
+	})
 	// Rule 3: 25 points if the total is a multiple of 0.25.
-	if isMultipleOf25Cents(receipt.Total) {
-		points += 25
-		breakdown += "25 points - total is a multiple of 0.25\n"
-	}
+	score("quarterMultipleTotal", func() (int64, []BreakdownLine) {
+		if isMultipleOf25Cents(receipt.Total) {
+			return 25, []BreakdownLine{{Points: 25, Description: "total is a multiple of 0.25"}}
+		}
+		return 0, nil
+	})
 	// Rule 4: 5 points for every two items on the receipt.
-	numItems := numItemsOnReceipt(receipt.Items)
-	itemPoints := int64(numItems/2) * 5
-	points += itemPoints
-	breakdown += fmt.Sprintf("%d points - %d items (%d pairs @ 5 points each)\n", itemPoints, numItems, numItems/2)
+	score("itemPairs", func() (int64, []BreakdownLine) {
+		numItems := numItemsOnReceipt(receipt.Items)
+		itemPoints := int64(numItems/2) * 5
+		return itemPoints, []BreakdownLine{{Points: itemPoints, Description: fmt.Sprintf("%d items (%d pairs @ 5 points each)", numItems, numItems/2)}}
+	})
 	// Rule 5: If the trimmed length of the item description is a multiple of 3, multiply the price by 0.2 and round up to the nearest integer. The result is the number of points earned.
-	for _, item := range receipt.Items {
-		if isMultipleOf3(item.ShortDescription) {
-			itemPrice := stringToFloat(item.Price)
-			itemPoints := int64(math.Ceil(itemPrice * 0.2))
-			points += itemPoints
-			breakdown += fmt.Sprintf("%d points - item description (%s) is a multiple of 3, price: %.2f\n", itemPoints, item.ShortDescription, itemPrice)
+	score("itemDescriptions", func() (int64, []BreakdownLine) {
+		descriptionPoints := int64(0)
+		var descriptionBreakdown []BreakdownLine
+		for _, item := range receipt.Items {
+			if IsMultipleOf3(item.ShortDescription) {
+				itemPrice := stringToFloat(item.Price)
+				itemPoints := int64(math.Ceil(itemPrice * 0.2))
+				descriptionPoints += itemPoints
+				descriptionBreakdown = append(descriptionBreakdown, BreakdownLine{Points: itemPoints, Description: fmt.Sprintf("item description (%s) is a multiple of 3, price: %.2f", item.ShortDescription, itemPrice)})
+			}
 		}
-	}
+		return descriptionPoints, descriptionBreakdown
+	})
 	// Rule 6: 6 points if the day in the purchase date is odd.
-	if isOddDay(receipt.PurchaseDate) {
-		points += 6
-		breakdown += "6 points - purchase date day is odd\n"
-	}
+	score("oddDay", func() (int64, []BreakdownLine) {
+		if isOddDay(receipt.PurchaseDate) {
+			return 6, []BreakdownLine{{Points: 6, Description: "purchase date day is odd"}}
+		}
+		return 0, nil
+	})
 	// Rule 7: 10 points if the time of purchase is after 2:00pm and before 4:00pm.
-	if isBetween2And4PM(receipt.PurchaseTime) {
-		points += 10
-		breakdown += "10 points - purchase time is between 2:00pm and 4:00pm\n"
+	score("afternoonPurchase", func() (int64, []BreakdownLine) {
+		if isBetween2And4PM(receipt.PurchaseTime) {
+			return 10, []BreakdownLine{{Points: 10, Description: "purchase time is between 2:00pm and 4:00pm"}}
+		}
+		return 0, nil
+	})
+
+	span.SetAttributes(attribute.Int64("receipt.points", points))
+
+	return points, breakdown
+}
+
+// Cap is points an earning policy withheld from a receipt after it was scored
+type Cap struct {
+	Points int64
+	Reason string
+}
+
+// ApplyCaps deducts caps from the result of CalculatePoints, listing each one after the rules
+func ApplyCaps(points int64, breakdown []BreakdownLine, caps []Cap) (int64, []BreakdownLine) {
+	if len(caps) == 0 {
+		return points, breakdown
 	}
 
-	return points, fmt.Sprintf("Total Points: %d\nBreakdown:\n%s+ ---------\n= %d points\n", points, breakdown, points)
+	// Copy the rule lines so the caller's breakdown is left as it was
+	capped := append([]BreakdownLine{}, breakdown...)
+	for _, c := range caps {
+		points -= c.Points
+		capped = append(capped, BreakdownLine{Points: -c.Points, Description: c.Reason})
+	}
+	return points, capped
+}
+
+// FormatBreakdown prints a breakdown returned by CalculatePoints or ApplyCaps, one line per rule or cap
+func FormatBreakdown(points int64, breakdown []BreakdownLine) string {
+	var lines strings.Builder
+	for _, line := range breakdown {
+		fmt.Fprintf(&lines, "%d points - %s\n", line.Points, line.Description)
+	}
+	return fmt.Sprintf("Total Points: %d\nBreakdown:\n%s+ ---------\n= %d points\n", points, lines.String(), points)
 }
 
 func countAlphaNumeric(s string) int64 {
@@ -93,7 +174,9 @@ func numItemsOnReceipt(items []models.Item) int {
 	return len(items)
 }
 
-func isMultipleOf3(s string) bool {
+// IsMultipleOf3 reports whether an item description earns points under rule 5, its
+// length without spaces being a multiple of 3
+func IsMultipleOf3(s string) bool {
 	// Remove whitespace
 	length := len(strings.ReplaceAll(s, " ", ""))
 	if length > 0 {
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"receipt-processor/pkg/models"
+	"reflect"
 	"testing"
 )
 
@@ -217,9 +218,9 @@ func TestIsMultipleOf3(t *testing.T) {
 
 	for _, testCase := range testCases {
 		t.Run(testCase.input, func(t *testing.T) {
-			actual := isMultipleOf3(testCase.input)
+			actual := IsMultipleOf3(testCase.input)
 			if actual != testCase.expected {
-				t.Errorf("Expected isMultipleOf3(%s) to be %v, but got %v", testCase.input, testCase.expected, actual)
+				t.Errorf("Expected IsMultipleOf3(%s) to be %v, but got %v", testCase.input, testCase.expected, actual)
 			}
 		})
 	}
@@ -271,3 +272,65 @@ func TestIsBetween2And4PM(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyCaps(t *testing.T) {
+	points, breakdown := CalculatePoints(models.Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2022-01-02",
+		PurchaseTime: "11:11",
+		Items: []models.Item{
+			{ShortDescription: "Pepsi - 12-oz", Price: "1.25"},
+		},
+		Total: "1.25",
+	})
+
+	capped, cappedBreakdown := ApplyCaps(points, breakdown, []Cap{{Points: 11, Reason: "daily cap of 20 points reached, 0 already earned today"}})
+	if capped != 20 {
+		t.Errorf("Expected 20 points, got %d", capped)
+	}
+
+	expected := "Total Points: 20\nBreakdown:\n" +
+		"6 points - retailer name (Target) has 6 alphanumeric characters\n" +
+		"25 points - total is a multiple of 0.25\n" +
+		"0 points - 1 items (0 pairs @ 5 points each)\n" +
+		"-11 points - daily cap of 20 points reached, 0 already earned today\n" +
+		"+ ---------\n= 20 points\n"
+	if formatted := FormatBreakdown(capped, cappedBreakdown); formatted != expected {
+		t.Errorf("Expected breakdown:\n%s\ngot:\n%s", expected, formatted)
+	}
+
+	// Without caps the result is unchanged
+	if uncapped, uncappedBreakdown := ApplyCaps(points, breakdown, nil); uncapped != points || !reflect.DeepEqual(uncappedBreakdown, breakdown) {
+		t.Errorf("Expected ApplyCaps without caps to change nothing")
+	}
+}
+
+func TestCalculatePointsBreakdown(t *testing.T) {
+	// A description that looks like a breakdown line stays part of its own rule
+	points, breakdown := CalculatePoints(models.Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2022-01-02",
+		PurchaseTime: "11:11",
+		Items: []models.Item{
+			{ShortDescription: "ab\n900 points - bonus", Price: "1.00"},
+		},
+		Total: "1.13",
+	})
+
+	expected := []BreakdownLine{
+		{Points: 6, Description: "retailer name (Target) has 6 alphanumeric characters"},
+		{Points: 0, Description: "1 items (0 pairs @ 5 points each)"},
+		{Points: 1, Description: "item description (ab\n900 points - bonus) is a multiple of 3, price: 1.00"},
+	}
+	if !reflect.DeepEqual(breakdown, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, breakdown)
+	}
+
+	total := int64(0)
+	for _, line := range breakdown {
+		total += line.Points
+	}
+	if total != points {
+		t.Errorf("Expected the breakdown to add up to %d points, got %d", points, total)
+	}
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DateLayout is the canonical purchaseDate format that receipts are scored and stored in
+	DateLayout = time.DateOnly
+	// TimeLayout is the canonical purchaseTime format that receipts are scored and stored in
+	TimeLayout = "15:04"
+)
+
+// Layouts are the purchaseDate and purchaseTime formats receipts are accepted in, tried in order.
+// Layouts name the meridiem as PM, since input is matched case-insensitively.
+type Layouts struct {
+	Dates []string
+	Times []string
+}
+
+// DefaultLayouts accept the canonical formats along with those common on scanned receipts,
+// such as 03/20/2022, 2:33 PM and 14:33:05
+var DefaultLayouts = Layouts{
+	Dates: []string{DateLayout, "1/2/2006"},
+	Times: []string{TimeLayout, "15:04:05", "3:04 PM", "3:04PM", "3:04:05 PM"},
+}
+
+// NormalizeDate returns the date in DateLayout, or false when it matches none of the date layouts
+func (l Layouts) NormalizeDate(value string) (string, bool) {
+	return normalize(value, l.Dates, DateLayout)
+}
+
+// NormalizeTime returns the time in TimeLayout, or false when it matches none of the time layouts.
+// Seconds are dropped.
+func (l Layouts) NormalizeTime(value string) (string, bool) {
+	return normalize(value, l.Times, TimeLayout)
+}
+
+// Validate checks that every date layout records the year, month and day, and every time layout
+// the hour and minute
+func (l Layouts) Validate() error {
+	if len(l.Dates) == 0 || len(l.Times) == 0 {
+		return errors.New("at least one date and one time layout are required")
+	}
+
+	reference := time.Date(2006, time.March, 21, 14, 33, 0, 0, time.UTC)
+	for _, layout := range l.Dates {
+		if date, ok := normalize(reference.Format(layout), []string{layout}, DateLayout); !ok || date != reference.Format(DateLayout) {
+			return fmt.Errorf("date layout %q must include the year, month and day", layout)
+		}
+	}
+	for _, layout := range l.Times {
+		if clock, ok := normalize(reference.Format(layout), []string{layout}, TimeLayout); !ok || clock != reference.Format(TimeLayout) {
+			return fmt.Errorf("time layout %q must include the hour and minute", layout)
+		}
+	}
+	return nil
+}
+
+func normalize(value string, layouts []string, canonical string) (string, bool) {
+	// Meridiems only parse in upper case, so "2:33 pm" is read as "2:33 PM"
+	value = strings.ToUpper(strings.TrimSpace(value))
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.Format(canonical), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	// Define slice of test cases
+	testCases := []struct {
+		description string
+		normalize   func(string) (string, bool)
+		value       string
+		expected    string
+		expectedOK  bool
+	}{
+		{"Canonical date", DefaultLayouts.NormalizeDate, "2022-03-20", "2022-03-20", true},
+		{"US date", DefaultLayouts.NormalizeDate, "03/20/2022", "2022-03-20", true},
+		{"US date without leading zeros", DefaultLayouts.NormalizeDate, "3/5/2022", "2022-03-05", true},
+		{"Invalid date", DefaultLayouts.NormalizeDate, "2022-13-01", "", false},
+		{"Unaccepted date layout", DefaultLayouts.NormalizeDate, "20.03.2022", "", false},
+		{"Canonical time", DefaultLayouts.NormalizeTime, "14:33", "14:33", true},
+		{"Time with seconds", DefaultLayouts.NormalizeTime, "14:33:59", "14:33", true},
+		{"12-hour time", DefaultLayouts.NormalizeTime, "2:33 PM", "14:33", true},
+		{"Lower case 12-hour time", DefaultLayouts.NormalizeTime, " 2:33pm ", "14:33", true},
+		{"12-hour morning time", DefaultLayouts.NormalizeTime, "12:05 AM", "00:05", true},
+		{"Invalid time", DefaultLayouts.NormalizeTime, "25:00", "", false},
+		{"Only the configured layouts are accepted", Layouts{Times: []string{TimeLayout}}.NormalizeTime, "2:33 PM", "", false},
+	}
+
+	// Iterate through test cases
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			normalized, ok := testCase.normalize(testCase.value)
+			if normalized != testCase.expected || ok != testCase.expectedOK {
+				t.Errorf("Expected %q (%t), got %q (%t)", testCase.expected, testCase.expectedOK, normalized, ok)
+			}
+		})
+	}
+}
+
+func TestValidateLayouts(t *testing.T) {
+	// Define slice of test cases
+	testCases := []struct {
+		description string
+		layouts     Layouts
+		expectError bool
+	}{
+		{"Default layouts", DefaultLayouts, false},
+		{"European dates", Layouts{Dates: []string{"02.01.2006"}, Times: []string{TimeLayout}}, false},
+		{"Date layout without a year", Layouts{Dates: []string{"01/02"}, Times: []string{TimeLayout}}, true},
+		{"Time layout given as a date", Layouts{Dates: []string{DateLayout}, Times: []string{DateLayout}}, true},
+		{"Lower case meridiem", Layouts{Dates: []string{DateLayout}, Times: []string{"3:04 pm"}}, true},
+		{"No time layouts", Layouts{Dates: []string{DateLayout}}, true},
+	}
+
+	// Iterate through test cases
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := testCase.layouts.Validate()
+			if (err != nil) != testCase.expectError {
+				t.Errorf("Expected error %t, got %v", testCase.expectError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	ReceiptProcessed EventType = "receipt.processed"
+	ReceiptRejected  EventType = "receipt.rejected"
+	PointsRedeemed   EventType = "points.redeemed"
+)
+
+// Headers sent with every delivery. The signature is the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the subscription's secret, prefixed with "sha256=".
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+)
+
+// Subscription sends the listed events to URL, or every event when Events is empty
+type Subscription struct {
+	ID     string      `json:"id"`
+	URL    string      `json:"url"`
+	Secret string      `json:"secret"`
+	Events []EventType `json:"events"`
+}
+
+func (s Subscription) wants(eventType EventType) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, wanted := range s.Events {
+		if wanted == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type subscriptionsFile struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+}
+
+// LoadSubscriptions reads a webhooks file of the form
+//
+//	{"subscriptions": [{"id": "partner", "url": "https://partner.example/hooks", "secret": "...", "events": ["receipt.processed"]}]}
+func LoadSubscriptions(path string) ([]Subscription, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading webhooks file: %w", err)
+	}
+	var file subscriptionsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing webhooks file: %w", err)
+	}
+	for _, subscription := range file.Subscriptions {
+		if subscription.ID == "" || subscription.URL == "" || subscription.Secret == "" {
+			return nil, fmt.Errorf("webhook %q needs an id, url and secret", subscription.ID)
+		}
+		for _, eventType := range subscription.Events {
+			if eventType != ReceiptProcessed && eventType != ReceiptRejected && eventType != PointsRedeemed {
+				return nil, fmt.Errorf("webhook %q: unknown event %q", subscription.ID, eventType)
+			}
+		}
+	}
+	return file.Subscriptions, nil
+}
+
+// Event is the JSON body POSTed to subscribers
+type Event struct {
+	ID        string    `json:"id"`
+	Type      EventType `json:"type"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      any       `json:"data"`
+}
+
+// Delivery records one attempt to send an event to a subscriber
+type Delivery struct {
+	EventID        string
+	EventType      EventType
+	SubscriptionID string
+	URL            string
+	Attempt        int
+	// StatusCode is zero when no response was received
+	StatusCode int
+	Error      string
+	At         time.Time
+}
+
+// DeadLetter is an event a subscriber never accepted
+type DeadLetter struct {
+	Event          Event
+	SubscriptionID string
+	Attempts       int
+	LastError      string
+	At             time.Time
+}
+
+type Options struct {
+	// MaxAttempts is how many times an event is sent before it is dead-lettered
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, doubling for each one after
+	InitialBackoff time.Duration
+	Client         *http.Client
+}
+
+// maxDeliveryLog bounds the delivery log, dropping the oldest attempts first
+const maxDeliveryLog = 1000
+
+// Dispatcher delivers events to subscribers in the background. A nil Dispatcher
+// discards every event.
+type Dispatcher struct {
+	subscriptions []Subscription
+	options       Options
+
+	mu          sync.Mutex
+	deliveries  []Delivery
+	deadLetters []DeadLetter
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	pending sync.WaitGroup
+	now     func() time.Time
+}
+
+func NewDispatcher(subscriptions []Subscription, options Options) *Dispatcher {
+	if options.MaxAttempts < 1 {
+		options.MaxAttempts = 5
+	}
+	if options.InitialBackoff <= 0 {
+		options.InitialBackoff = time.Second
+	}
+	if options.Client == nil {
+		options.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		subscriptions: subscriptions,
+		options:       options,
+		ctx:           ctx,
+		cancel:        cancel,
+		now:           time.Now,
+	}
+}
+
+// Publish sends the event to every subscriber that wants it without waiting for them to answer
+func (d *Dispatcher) Publish(eventType EventType, data any) {
+	if d == nil {
+		return
+	}
+
+	event := Event{ID: uuid.NewString(), Type: eventType, CreatedAt: d.now().UTC(), Data: data}
+	body, err := json.Marshal(event)
+	if err != nil {
+		d.deadLetter(event, "", 0, err)
+		return
+	}
+
+	for _, subscription := range d.subscriptions {
+		if !subscription.wants(eventType) {
+			continue
+		}
+		d.pending.Add(1)
+		go d.deliver(subscription, event, body)
+	}
+}
+
+// Close abandons waiting retries, dead-lettering their events, and waits for attempts in flight
+func (d *Dispatcher) Close() {
+	if d == nil {
+		return
+	}
+	d.cancel()
+	d.pending.Wait()
+}
+
+// Wait blocks until every published event has been delivered or dead-lettered
+func (d *Dispatcher) Wait() {
+	if d == nil {
+		return
+	}
+	d.pending.Wait()
+}
+
+func (d *Dispatcher) deliver(subscription Subscription, event Event, body []byte) {
+	defer d.pending.Done()
+
+	backoff := d.options.InitialBackoff
+	var err error
+	for attempt := 1; attempt <= d.options.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-time.After(backoff):
+				backoff *= 2
+			case <-d.ctx.Done():
+				d.deadLetter(event, subscription.ID, attempt-1, fmt.Errorf("gave up on shutdown after: %w", err))
+				return
+			}
+		}
+
+		err = d.send(subscription, event, body, attempt)
+		if err == nil {
+			return
+		}
+	}
+	d.deadLetter(event, subscription.ID, d.options.MaxAttempts, err)
+}
+
+func (d *Dispatcher) send(subscription Subscription, event Event, body []byte, attempt int) error {
+	delivery := Delivery{
+		EventID:        event.ID,
+		EventType:      event.Type,
+		SubscriptionID: subscription.ID,
+		URL:            subscription.URL,
+		Attempt:        attempt,
+		At:             d.now(),
+	}
+	err := d.post(subscription, event, body, &delivery)
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	d.mu.Lock()
+	d.deliveries = append(d.deliveries, delivery)
+	if len(d.deliveries) > maxDeliveryLog {
+		d.deliveries = d.deliveries[len(d.deliveries)-maxDeliveryLog:]
+	}
+	d.mu.Unlock()
+	return err
+}
+
+func (d *Dispatcher) post(subscription Subscription, event Event, body []byte, delivery *Delivery) error {
+	request, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(delivery.At.Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventHeader, string(event.Type))
+	request.Header.Set(TimestampHeader, timestamp)
+	request.Header.Set(SignatureHeader, Sign(subscription.Secret, timestamp, body))
+
+	response, err := d.options.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+
+	delivery.StatusCode = response.StatusCode
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("subscriber answered %s", response.Status)
+	}
+	return nil
+}
+
+func (d *Dispatcher) deadLetter(event Event, subscriptionID string, attempts int, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.deadLetters = append(d.deadLetters, DeadLetter{
+		Event:          event,
+		SubscriptionID: subscriptionID,
+		Attempts:       attempts,
+		LastError:      err.Error(),
+		At:             d.now(),
+	})
+}
+
+// Deliveries returns the most recent delivery attempts, oldest first
+func (d *Dispatcher) Deliveries() []Delivery {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]Delivery(nil), d.deliveries...)
+}
+
+// DeadLetters returns the events subscribers never accepted, oldest first
+func (d *Dispatcher) DeadLetters() []DeadLetter {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]DeadLetter(nil), d.deadLetters...)
+}
+
+// Sign returns the signature header value for a delivery
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature was produced by Sign with the same secret, timestamp and body
+func Verify(secret, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
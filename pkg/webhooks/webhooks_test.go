@@ -0,0 +1,153 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// receiver is a subscriber endpoint that fails its first failures requests
+type receiver struct {
+	mu       sync.Mutex
+	failures int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	body, _ := io.ReadAll(request.Body)
+	r.requests = append(r.requests, request)
+	r.bodies = append(r.bodies, body)
+	if len(r.requests) <= r.failures {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestDispatcherDelivers(t *testing.T) {
+	// Define slice of test cases
+	testCases := []struct {
+		description         string
+		failures            int
+		expectedAttempts    int
+		expectedDeadLetters int
+	}{
+		{description: "Accepted first time", failures: 0, expectedAttempts: 1},
+		{description: "Retried until accepted", failures: 2, expectedAttempts: 3},
+		{description: "Dead-lettered after every attempt fails", failures: 10, expectedAttempts: 4, expectedDeadLetters: 1},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			subscriber := &receiver{failures: testCase.failures}
+			server := httptest.NewServer(subscriber)
+			defer server.Close()
+
+			dispatcher := NewDispatcher([]Subscription{
+				{ID: "partner", URL: server.URL, Secret: "s3cret", Events: []EventType{ReceiptProcessed}},
+			}, Options{MaxAttempts: 4, InitialBackoff: time.Millisecond})
+
+			dispatcher.Publish(ReceiptProcessed, map[string]any{"id": "r1", "points": 28})
+			// Not subscribed to redemptions
+			dispatcher.Publish(PointsRedeemed, map[string]any{"points": 10})
+			dispatcher.Wait()
+
+			if len(subscriber.requests) != testCase.expectedAttempts {
+				t.Fatalf("Expected %d requests, got %d", testCase.expectedAttempts, len(subscriber.requests))
+			}
+
+			// Every attempt is signed and carries the same event
+			for i, request := range subscriber.requests {
+				if !Verify("s3cret", request.Header.Get(TimestampHeader), subscriber.bodies[i], request.Header.Get(SignatureHeader)) {
+					t.Errorf("Attempt %d has an invalid signature", i+1)
+				}
+				var event Event
+				if err := json.Unmarshal(subscriber.bodies[i], &event); err != nil {
+					t.Fatalf("Error parsing event: %v", err)
+				}
+				if event.Type != ReceiptProcessed || request.Header.Get(EventHeader) != string(ReceiptProcessed) {
+					t.Errorf("Expected a %s event, got %s", ReceiptProcessed, event.Type)
+				}
+			}
+
+			deliveries := dispatcher.Deliveries()
+			if len(deliveries) != testCase.expectedAttempts || deliveries[len(deliveries)-1].Attempt != testCase.expectedAttempts {
+				t.Errorf("Expected %d logged attempts, got %+v", testCase.expectedAttempts, deliveries)
+			}
+			if deadLetters := dispatcher.DeadLetters(); len(deadLetters) != testCase.expectedDeadLetters {
+				t.Errorf("Expected %d dead letters, got %+v", testCase.expectedDeadLetters, deadLetters)
+			}
+		})
+	}
+}
+
+func TestDispatcherCloseAbandonsRetries(t *testing.T) {
+	server := httptest.NewServer(&receiver{failures: 10})
+	defer server.Close()
+
+	dispatcher := NewDispatcher([]Subscription{{ID: "partner", URL: server.URL, Secret: "s3cret"}}, Options{InitialBackoff: time.Hour})
+	dispatcher.Publish(PointsRedeemed, map[string]any{"points": 10})
+
+	// Wait for the first attempt, which fails and schedules a retry an hour away
+	for len(dispatcher.Deliveries()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	dispatcher.Close()
+
+	deadLetters := dispatcher.DeadLetters()
+	if len(deadLetters) != 1 || deadLetters[0].Attempts != 1 {
+		t.Errorf("Expected the event to be dead-lettered after one attempt, got %+v", deadLetters)
+	}
+}
+
+func TestSignature(t *testing.T) {
+	body := []byte(`{"id":"e1"}`)
+	signature := Sign("s3cret", "1700000000", body)
+
+	if !Verify("s3cret", "1700000000", body, signature) {
+		t.Error("Expected signature to verify")
+	}
+	if Verify("other", "1700000000", body, signature) {
+		t.Error("Expected a different secret to fail")
+	}
+	if Verify("s3cret", "1700000001", body, signature) {
+		t.Error("Expected a different timestamp to fail")
+	}
+}
+
+func TestLoadSubscriptions(t *testing.T) {
+	// Define slice of test cases
+	testCases := []struct {
+		description string
+		contents    string
+		expectError bool
+	}{
+		{description: "Valid file", contents: `{"subscriptions": [{"id": "partner", "url": "http://localhost:9000", "secret": "s3cret", "events": ["receipt.processed"]}]}`},
+		{description: "Missing secret", contents: `{"subscriptions": [{"id": "partner", "url": "http://localhost:9000"}]}`, expectError: true},
+		{description: "Unknown event", contents: `{"subscriptions": [{"id": "partner", "url": "http://localhost:9000", "secret": "s3cret", "events": ["receipt.deleted"]}]}`, expectError: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "webhooks.json")
+			if err := os.WriteFile(path, []byte(testCase.contents), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadSubscriptions(path)
+			if (err != nil) != testCase.expectError {
+				t.Errorf("Expected error %v, got %v", testCase.expectError, err)
+			}
+		})
+	}
+}